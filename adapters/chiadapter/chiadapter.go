@@ -0,0 +1,88 @@
+// Package chiadapter binds the path params of requests routed by github.com/go-chi/chi, whose route pattern
+// and params are held by the chi RouteContext instead of the net/http 1.22 pattern fields.
+//
+// Register sets the PathMatcher of chi patterns on the binder, and NewBindableRequest reads the pattern and
+// params from the RouteContext. The `*` catch-all is bound by the WildcardParam name, `*` tag names
+// capturing every key:
+//
+//	chiadapter.Register(b)
+//
+//	type File struct {
+//		Bucket string `param:"bucket"`
+//		Path   string `param:"wildcard"`
+//	}
+//
+//	r.Get("/buckets/{bucket}/*", func(w http.ResponseWriter, r *http.Request) {
+//		var file File
+//		err := chiadapter.Bind(b, r, &file)
+//	})
+package chiadapter
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gobigbang/binder"
+)
+
+// PathMatcher matches the `{id}` params of chi patterns, including the regexp constraints holding braces,
+// i.e. `{code:[a-z]{3}}`.
+var PathMatcher = regexp.MustCompile(`\{((?:[^{}]|\{[^{}]*\})+)\}`)
+
+// WildcardParam is the name the `*` catch-all of chi patterns is bound with, i.e. `param:"wildcard"`.
+var WildcardParam = "wildcard"
+
+// Register sets the PathMatcher of chi patterns on the binder.
+func Register(b *binder.DefaultBinder) {
+	b.PathMatcher = PathMatcher
+}
+
+// BindableRequest is an http.Request routed by chi, whose path pattern and values are read from the
+// chi RouteContext. Requests without RouteContext fall back to the net/http pattern fields. The other
+// methods, including HTTPRequest, are the ones of the embedded binder.HttpBindableRequest.
+type BindableRequest struct {
+	binder.HttpBindableRequest
+}
+
+// NewBindableRequest returns the bindable request of a request routed by chi.
+func NewBindableRequest(r *http.Request) BindableRequest {
+	return BindableRequest{HttpBindableRequest: binder.NewHttpBindableRequest(r)}
+}
+
+// GetPathPattern returns the route pattern matched by chi, joining the patterns of the mounted routers,
+// with the trailing `*` catch-all written as the `{wildcard...}` remaining segments, i.e. `/files/{wildcard...}`.
+func (r BindableRequest) GetPathPattern() string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return r.HttpBindableRequest.GetPathPattern()
+	}
+	pattern := rctx.RoutePattern()
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		pattern = prefix + "{" + WildcardParam + "...}"
+	}
+	return pattern
+}
+
+// GetPathValue returns the value of the chi URL param, the WildcardParam being the `*` catch-all.
+func (r BindableRequest) GetPathValue(key string) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return r.HttpBindableRequest.GetPathValue(key)
+	}
+	if key == WildcardParam {
+		key = "*"
+	}
+	return rctx.URLParam(key)
+}
+
+// Bind binds the request routed by chi with the binder, see binder.Binder.Bind.
+func Bind(b binder.Binder, r *http.Request, i interface{}) error {
+	return b.Bind(NewBindableRequest(r), i)
+}
+
+// BindPathParams binds the chi URL params of the request with the binder.
+func BindPathParams(b binder.Binder, r *http.Request, i interface{}) error {
+	return b.BindPathParams(NewBindableRequest(r), i)
+}
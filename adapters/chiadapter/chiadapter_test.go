@@ -0,0 +1,97 @@
+package chiadapter_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gobigbang/binder"
+	"github.com/gobigbang/binder/adapters/chiadapter"
+)
+
+func TestBind(t *testing.T) {
+	type File struct {
+		Bucket  string `param:"bucket"`
+		Region  string `param:"region"`
+		ID      int    `param:"id"`
+		Path    string `param:"wildcard"`
+		Version string `query:"version"`
+	}
+	b := binder.NewBinder()
+	chiadapter.Register(b)
+
+	var data File
+	var err error
+	files := chi.NewRouter()
+	files.Get("/{id:[0-9]+}/*", func(w http.ResponseWriter, r *http.Request) {
+		err = chiadapter.Bind(b, r, &data)
+	})
+	router := chi.NewRouter()
+	router.Mount("/{region:[a-z]{2}}/buckets/{bucket}/files", files)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/eu/buckets/media/files/7/docs/a.txt?version=2", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data != (File{Bucket: "media", Region: "eu", ID: 7, Path: "docs/a.txt", Version: "2"}) {
+		t.Fatalf("expected the chi params to be bound, got %+v", data)
+	}
+}
+
+func TestBindWithoutRouteContext(t *testing.T) {
+	type User struct {
+		ID string `param:"id"`
+	}
+	b := binder.NewBinder()
+	chiadapter.Register(b)
+	req := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+	req.Pattern = "/users/{id}"
+	req.SetPathValue("id", "7")
+
+	var data User
+	if err := chiadapter.BindPathParams(b, req, &data); err != nil || data.ID != "7" {
+		t.Fatalf("expected the net/http path values, got %+v, %v", data, err)
+	}
+}
+
+func TestBindBody(t *testing.T) {
+	type Upload struct {
+		Bucket string `param:"bucket"`
+		Name   string `form:"name"`
+		Raw    []byte `body:"raw"`
+	}
+	b := binder.NewBinder()
+	chiadapter.Register(b)
+
+	var data Upload
+	var err error
+	router := chi.NewRouter()
+	router.Post("/buckets/{bucket}", func(w http.ResponseWriter, r *http.Request) {
+		err = chiadapter.Bind(b, r, &data)
+	})
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/buckets/media", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req
+	}
+
+	router.ServeHTTP(httptest.NewRecorder(), newRequest("name=report"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Bucket != "media" || data.Name != "report" || string(data.Raw) != "name=report" {
+		t.Fatalf("expected the raw form body alongside the form values, got %+v", data)
+	}
+
+	// the size of a chunked body is checked while the request parses its form
+	b.MaxBodySize = 8
+	req := newRequest("name=" + strings.Repeat("x", 16))
+	req.ContentLength = -1
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	if !errors.Is(err, binder.ErrBodyTooLarge) {
+		t.Fatalf("expected the body to exceed MaxBodySize, got %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/gobigbang/binder/adapters/chiadapter
+
+go 1.23.2
+
+require (
+	github.com/go-chi/chi/v5 v5.2.3
+	github.com/gobigbang/binder v0.0.0
+)
+
+replace github.com/gobigbang/binder => ../..
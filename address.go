@@ -0,0 +1,41 @@
+package binder
+
+import (
+	"net/mail"
+	"net/url"
+	"reflect"
+)
+
+var (
+	urlType         = reflect.TypeOf(url.URL{})
+	mailAddressType = reflect.TypeOf(mail.Address{})
+)
+
+// ConvertURL converts the value into a url.URL. Only absolute URLs, with a scheme, are accepted.
+func ConvertURL(values []string, dst reflect.Value, _ reflect.StructField) error {
+	if len(values) == 0 || values[0] == "" {
+		return nil
+	}
+	u, err := url.Parse(values[0])
+	if err == nil && u.Scheme == "" {
+		err = ErrRelativeURL
+	}
+	if err != nil {
+		return &ParseError{Type: urlType, Value: values[0], Err: err}
+	}
+	dst.Set(reflect.ValueOf(*u))
+	return nil
+}
+
+// ConvertMailAddress converts the value into a mail.Address, e.g. `Joe <joe@example.com>` or `joe@example.com`.
+func ConvertMailAddress(values []string, dst reflect.Value, _ reflect.StructField) error {
+	if len(values) == 0 || values[0] == "" {
+		return nil
+	}
+	address, err := mail.ParseAddress(values[0])
+	if err != nil {
+		return &ParseError{Type: mailAddressType, Value: values[0], Err: err}
+	}
+	dst.Set(reflect.ValueOf(*address))
+	return nil
+}
@@ -0,0 +1,54 @@
+package binder
+
+import (
+	"encoding/base64"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// Credentials of the Authorization header bound with the auth tag, i.e. `auth:"bearer"`.
+const (
+	AuthBasicUser = "basic_user" // user of the Basic scheme
+	AuthBasicPass = "basic_pass" // password of the Basic scheme
+	AuthBearer    = "bearer"     // token of the Bearer scheme
+)
+
+// GetAuthValues returns the credentials of the Authorization header. Schemes are matched whatever their
+// case, and malformed Basic credentials are ignored like http.Request.BasicAuth does.
+func (b *DefaultBinder) GetAuthValues(r BindableRequest) map[string][]string {
+	scheme, credentials, ok := strings.Cut(http.Header(r.GetHeaders()).Get(HeaderAuthorization), " ")
+	if !ok {
+		return nil
+	}
+	credentials = strings.TrimSpace(credentials)
+	switch {
+	case strings.EqualFold(scheme, "Bearer") && credentials != "":
+		return map[string][]string{AuthBearer: {credentials}}
+	case strings.EqualFold(scheme, "Basic"):
+		decoded, err := base64.StdEncoding.DecodeString(credentials)
+		if err != nil {
+			return nil
+		}
+		user, pass, ok := strings.Cut(string(decoded), ":")
+		if !ok {
+			return nil
+		}
+		return map[string][]string{AuthBasicUser: {user}, AuthBasicPass: {pass}}
+	}
+	return nil
+}
+
+// BindAuth binds the credentials of the Authorization header to fields with the auth tag, i.e. `auth:"basic_user"`.
+// Only struct destinations are bound.
+func (b *DefaultBinder) BindAuth(r BindableRequest, i interface{}) error {
+	if typ := reflect.TypeOf(i); typ == nil || typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	ctx, release := b.bindContext(r)
+	defer release()
+	if err := b.bindData(ctx, i, b.GetAuthValues(r), b.AuthTagName, nil); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package binder_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gobigbang/binder"
+)
+
+type benchFlat struct {
+	ID      int64   `query:"id"`
+	Name    string  `query:"name"`
+	Email   string  `query:"email"`
+	Age     int     `query:"age"`
+	Score   float64 `query:"score"`
+	Active  bool    `query:"active"`
+	Country string  `query:"country"`
+	Page    int     `query:"page"`
+}
+
+type benchItem struct {
+	Name  string  `form:"name" json:"name"`
+	Qty   int     `form:"qty" json:"qty"`
+	Price float64 `form:"price" json:"price"`
+}
+
+type benchDeep struct {
+	User struct {
+		Name    string `form:"name"`
+		Address struct {
+			City string `form:"city"`
+			Zip  string `form:"zip"`
+		} `form:"address"`
+		Tags []string `form:"tags"`
+	} `form:"user"`
+	Items []benchItem `form:"items"`
+}
+
+type benchUpload struct {
+	Title     string                  `form:"title"`
+	Documents []*multipart.FileHeader `form:"documents"`
+}
+
+type benchOrder struct {
+	ID    int64       `json:"id"`
+	Items []benchItem `json:"items"`
+}
+
+const flatQuery = "/?id=1&name=John&email=john@example.com&age=30&score=9.5&active=true&country=IT&page=2"
+
+func deepForm() string {
+	form := url.Values{
+		"user[name]":          {"John"},
+		"user[address][city]": {"Rome"},
+		"user[address][zip]":  {"00100"},
+		"user[tags][]":        {"a", "b", "c"},
+	}
+	for i := 0; i < 20; i++ {
+		form.Set(fmt.Sprintf("items[%d][name]", i), "item")
+		form.Set(fmt.Sprintf("items[%d][qty]", i), "2")
+		form.Set(fmt.Sprintf("items[%d][price]", i), "9.99")
+	}
+	return form.Encode()
+}
+
+func bigMultipart() ([]byte, string) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	writer.WriteField("title", "documents")
+	content := bytes.Repeat([]byte("x"), 256<<10)
+	for i := 0; i < 8; i++ {
+		part, _ := writer.CreateFormFile("documents", fmt.Sprintf("file%d.bin", i))
+		part.Write(content)
+	}
+	writer.Close()
+	return buf.Bytes(), writer.FormDataContentType()
+}
+
+func largeJSON() []byte {
+	order := benchOrder{ID: 1}
+	for i := 0; i < 1000; i++ {
+		order.Items = append(order.Items, benchItem{Name: "item", Qty: i, Price: 9.99})
+	}
+	body, _ := json.Marshal(order)
+	return body
+}
+
+func bindFlat() error {
+	var data benchFlat
+	return binder.BindHttpQueryParams(httptest.NewRequest(http.MethodGet, flatQuery, nil), &data)
+}
+
+func bindDeep(form string) error {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	var data benchDeep
+	return binder.BindHttpBody(req, &data)
+}
+
+func BenchmarkBindFlatStruct(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := bindFlat(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBindDeepForm(b *testing.B) {
+	form := deepForm()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := bindDeep(form); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBindDeepFormScratchPool(b *testing.B) {
+	form := deepForm()
+	scratchBinder := binder.NewBinder()
+	scratchBinder.UseScratchPool = true
+	httpBinder := &binder.HttpBinder{Binder: scratchBinder}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		var data benchDeep
+		if err := httpBinder.BindBody(req, &data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBindBigMultipart(b *testing.B) {
+	body, contentType := bigMultipart()
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		var data benchUpload
+		if err := binder.BindHttpBody(req, &data); err != nil {
+			b.Fatal(err)
+		}
+		req.MultipartForm.RemoveAll()
+	}
+}
+
+func BenchmarkBindLargeJSON(b *testing.B) {
+	body := largeJSON()
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		var data benchOrder
+		if err := binder.BindHttpBody(req, &data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// TestBindAllocations keeps the allocations of the benchmarked scenarios under a ceiling, about
+// twice the current figures, so regressions in bindData are caught by go test.
+func TestBindAllocations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping allocation assertions in short mode")
+	}
+	form := deepForm()
+	multipartBody, contentType := bigMultipart()
+	jsonBody := largeJSON()
+
+	for _, scenario := range []struct {
+		name string
+		max  float64
+		bind func() error
+	}{
+		{"FlatStruct", 60, bindFlat},
+		{"DeepForm", 1000, func() error { return bindDeep(form) }},
+		{"BigMultipart", 600, func() error {
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(multipartBody))
+			req.Header.Set("Content-Type", contentType)
+			var data benchUpload
+			defer func() {
+				if req.MultipartForm != nil {
+					req.MultipartForm.RemoveAll()
+				}
+			}()
+			return binder.BindHttpBody(req, &data)
+		}},
+		{"LargeJSON", 100, func() error {
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+			var data benchOrder
+			return binder.BindHttpBody(req, &data)
+		}},
+	} {
+		var err error
+		allocs := testing.AllocsPerRun(20, func() {
+			if bindErr := scenario.bind(); bindErr != nil {
+				err = bindErr
+			}
+		})
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", scenario.name, err)
+		}
+		if allocs > scenario.max {
+			t.Fatalf("%s: expected at most %.0f allocations, got %.0f", scenario.name, scenario.max, allocs)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package binder
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+var DefaultBigFloatPrecision uint = 256 // default mantissa precision of big.Float fields without a `prec` option
+
+// ConvertBigInt converts a base 10 integer of any size into a big.Int.
+func ConvertBigInt(values []string, dst reflect.Value, _ reflect.StructField) error {
+	if len(values) == 0 || values[0] == "" {
+		return nil
+	}
+	n, ok := new(big.Int).SetString(values[0], 10)
+	if !ok {
+		return fmt.Errorf("invalid integer %q", values[0])
+	}
+	dst.Set(reflect.ValueOf(n).Elem())
+	return nil
+}
+
+// ConvertBigFloat converts a decimal number into a big.Float with the precision set with
+// TagOption(field, "prec"), or DefaultBigFloatPrecision. The float policy of the binder applies.
+func (b *DefaultBinder) ConvertBigFloat(values []string, dst reflect.Value, field reflect.StructField) error {
+	if len(values) == 0 || values[0] == "" {
+		return nil
+	}
+	value := values[0]
+	prec := DefaultBigFloatPrecision
+	if tag := TagOption(field, "prec"); tag != "" {
+		p, err := strconv.ParseUint(tag, 10, 32)
+		if err != nil || p == 0 || p > big.MaxPrec {
+			return fmt.Errorf("invalid prec tag %q", tag)
+		}
+		prec = uint(p)
+	}
+	if !b.AllowFloatExponent && hasFloatExponent(value) {
+		return fmt.Errorf("%w: %q", ErrFloatExponent, value)
+	}
+	f, _, err := big.ParseFloat(value, 10, prec, big.ToNearestEven)
+	if err != nil {
+		return fmt.Errorf("invalid float %q", value)
+	}
+	if !b.AllowFloatNonFinite && f.IsInf() {
+		return fmt.Errorf("%w: %q", ErrFloatNonFinite, value)
+	}
+	dst.Set(reflect.ValueOf(f).Elem())
+	return nil
+}
@@ -4,17 +4,25 @@
 package binder
 
 import (
+	"context"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"io"
+	"mime"
 	"mime/multipart"
 	"net/url"
 	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 var ArrayMatcherRegexp = regexp.MustCompile(`\[([0-9]+)\]`)              // matches [0] to use in indexed arrays
 var MapMatcherRegexp = regexp.MustCompile(`\[([a-zA-Z0-9\-\_\.]+)\]`)    // matches [key] to use in maps and deep objects
 var ArrayNotationRegexp = regexp.MustCompile(`\[([a-zA-Z0-9\-\_\.]+)\]`) // matches [id] to use in deep objects
+var DeepObjectRegexp = regexp.MustCompile(`\[([^\[\]]+)\]`)              // matches [key] to use in OpenAPI deepObject query params
 var PathMatcherRegexp = regexp.MustCompile(`\{([^}]+)\}`)                // matches {id} to use in path parameters
 var DefaultDeepObjectSeparator = "."                                     // default separator for deep fields
 var DefaultBodySize = int64(32 << 20)                                    // 32 MB
@@ -22,6 +30,24 @@ var DefaultHeaderTagName = "header"                                      // defa
 var DefaultFormTagName = "form"                                          // default tag name for form
 var DefaultQueryTagName = "query"                                        // default tag name for query
 var DefaultParamTagName = "param"                                        // default tag name for param
+var DefaultBindTagName = "bind"                                          // default tag name to exclude fields from binding
+var DefaultXMLTagName = "xml"                                            // default tag name of the XML param overlay
+var DefaultRequestTagName = "request"                                    // default tag name for request metadata
+var DefaultCSRFTagName = "csrf"                                          // default tag name for the CSRF token
+var DefaultAuthTagName = "auth"                                          // default tag name for the Authorization credentials
+var DefaultClaimsTagName = "claims"                                      // default tag name for the claims of the ClaimsProvider
+var DefaultContextTagName = "ctx"                                        // default tag name for the values of the ContextValuesProvider
+var DefaultSessionTagName = "session"                                    // default tag name for the values of the SessionProvider
+var DefaultEnvTagName = "env"                                            // default tag name for the values of the Env lookup
+var DefaultConverterTagName = "convert"                                  // default tag name to select a named converter
+var DefaultSensitiveTagName = "sensitive"                                // default tag name marking the fields holding secrets
+var DefaultTimeLayout = time.RFC3339                                     // default layout to parse time.Time fields
+var DefaultMaxHeaderValues = 0                                           // max number of header values, 0 for no limit
+var DefaultMaxHeaderBytes = int64(1 << 20)                               // max total size of header keys and values, 1 MB
+var DefaultMethodOverrideField = "_method"                               // conventional form field to override the method of POST requests
+var DefaultCompatLevel = CompatV1                                        // behaviors of the binders without a CompatLevel
+var DefaultBindRequestMetadata = false                                   // adds BindRequestMetadata to the BindOrder of new binders, before the request sources
+var DefaultBindHeaders = false                                           // adds BindHeaders to the BindOrder of new binders, after the query params
 var MaxArraySize = 1000                                                  // max size of array
 
 // JSONSerializer is the interface that encodes and decodes JSON to and from interfaces.
@@ -30,6 +56,17 @@ type JSONSerializer interface {
 	Deserialize(r BindableRequest, i interface{}) error
 }
 
+// Deserializer is the interface that decodes a request body into interfaces.
+type Deserializer interface {
+	Deserialize(r BindableRequest, i interface{}) error
+}
+
+// MediaTypeDeserializer is implemented by deserializers that pick their decoding behavior from the
+// parsed Content-Type, i.e. the `version` param of a vendor media type.
+type MediaTypeDeserializer interface {
+	DeserializeMediaType(r BindableRequest, mediaType string, params map[string]string, i interface{}) error
+}
+
 // BindableRequest is the interface that wraps the basic methods required for
 // a request to be bindable.
 //
@@ -44,14 +81,160 @@ type BindableRequest interface {
 	GetContentType() string
 	GetForm() (url.Values, error)
 	GetMultipartForm(maxBodySize int64) (*multipart.Form, error)
+	GetProto() string      // protocol of the request, i.e. `HTTP/1.1`, empty when unknown
+	GetRemoteAddr() string // network address of the peer, usually `ip:port`, empty when unknown
+}
+
+// ContextRequest is implemented by bindable requests carrying a context, like HttpBindableRequest.
+type ContextRequest interface {
+	Context() context.Context
+}
+
+// RequestContext returns the context of the request, or context.Background() if it has none.
+func RequestContext(r BindableRequest) context.Context {
+	if cr, ok := r.(ContextRequest); ok {
+		if ctx := cr.Context(); ctx != nil {
+			return ctx
+		}
+	}
+	return context.Background()
+}
+
+// PostFormRequest is implemented by bindable requests able to return the urlencoded form values
+// of the body alone, without the URL query values merged by GetForm.
+type PostFormRequest interface {
+	GetPostForm() (url.Values, error)
+}
+
+// MediaTypeRequest is implemented by bindable requests exposing their parsed Content-Type.
+type MediaTypeRequest interface {
+	GetMediaType() (string, map[string]string)
+}
+
+// mediaTypeRequest wraps a request with its already parsed Content-Type.
+type mediaTypeRequest struct {
+	BindableRequest
+	mediaType string
+	params    map[string]string
+	body      io.Reader
+	length    *contentLengthReader // Content-Length check of the body, kept when a body processor replaces it
+}
+
+// GetBody returns the body of the wrapped request, checking it against its Content-Length when known.
+func (r *mediaTypeRequest) GetBody() io.Reader {
+	if r.body == nil {
+		r.body = r.BindableRequest.GetBody()
+		if length := r.GetContentLength(); length > 0 && r.body != nil {
+			r.length = &contentLengthReader{reader: r.body, contentLength: length, remaining: length}
+			r.body = r.length
+		}
+	}
+	return r.body
+}
+
+func (r *mediaTypeRequest) unwrapRequest() BindableRequest {
+	return r.BindableRequest
+}
+
+func (r *mediaTypeRequest) GetMediaType() (string, map[string]string) {
+	return r.mediaType, r.params
+}
+
+// Context returns the context of the wrapped request.
+func (r *mediaTypeRequest) Context() context.Context {
+	return RequestContext(r.BindableRequest)
+}
+
+// ParseMediaType parses a Content-Type value into its lower case media type and params
+// (boundary, charset, version...). Malformed params are ignored.
+func ParseMediaType(contentType string) (string, map[string]string) {
+	mediatype, params, err := mime.ParseMediaType(contentType)
+	if err != nil && !errors.Is(err, mime.ErrInvalidMediaParameter) {
+		// fallback to the media type without params
+		base, _, _ := strings.Cut(contentType, ";")
+		mediatype = strings.ToLower(strings.TrimSpace(base))
+	}
+	if params == nil {
+		params = map[string]string{}
+	}
+	return mediatype, params
+}
+
+// GetMediaType returns the media type and params of the request Content-Type.
+func GetMediaType(r BindableRequest) (string, map[string]string) {
+	if mr, ok := r.(MediaTypeRequest); ok {
+		return mr.GetMediaType()
+	}
+	return ParseMediaType(r.GetContentType())
 }
 
+// SparseArrayPolicy defines how indexed notation skipping indices (`items[0]`, `items[5]`) is bound to slices.
+type SparseArrayPolicy int
+
+const (
+	SparseArrayZeroFill SparseArrayPolicy = iota // allocates the slice up to the highest index, leaving zero values in the gaps
+	SparseArrayCompact                           // keeps the elements in index order without gaps
+	SparseArrayError                             // returns ErrSparseArray when an index is missing
+)
+
+// ArraySizePolicy defines how indices exceeding MaxArraySize are handled.
+type ArraySizePolicy int
+
+const (
+	ArraySizeError    ArraySizePolicy = iota // returns an error when an index exceeds MaxArraySize
+	ArraySizeTruncate                        // drops the elements exceeding MaxArraySize, recording a warning in the BindReport
+)
+
+// StringSizePolicy defines how string values exceeding the `maxbytes` of their field are handled.
+type StringSizePolicy int
+
+const (
+	StringSizeError    StringSizePolicy = iota // returns a *FieldSizeError
+	StringSizeTruncate                         // truncates the value on a character boundary, recording a warning in the BindReport
+)
+
+// MergePolicy defines how the sources of a Bind set the fields already set by an earlier source.
+type MergePolicy int
+
+const (
+	MergeLastWins        MergePolicy = iota // the later sources override the earlier ones
+	MergeFirstWins                          // the fields keep the value of the first source setting them
+	MergeErrorOnConflict                    // returns a *MergeConflictError when sources set different values
+	MergeSkipIfNonZero                      // the fields with a non-zero value are never overridden, including initial values
+)
+
+// ReadOnlyPolicy defines how values supplied by the client for `,readonly` fields are handled.
+type ReadOnlyPolicy int
+
+const (
+	ReadOnlyIgnore ReadOnlyPolicy = iota // leaves the field untouched, keeping the server-set value
+	ReadOnlyError                        // returns a *ReadOnlyFieldError
+)
+
 type BindFunc func(r BindableRequest, i interface{}) error
 
-type DefaultJSONSerializer struct{}
+type DefaultJSONSerializer struct {
+	// StrictJSON rejects objects with keys that do not match any destination field,
+	// reporting the first one as an *UnknownFieldError.
+	StrictJSON bool
+}
 
-func (DefaultJSONSerializer) Deserialize(r BindableRequest, i interface{}) error {
-	return json.NewDecoder(r.GetBody()).Decode(i)
+func (s DefaultJSONSerializer) Deserialize(r BindableRequest, i interface{}) error {
+	decoder := json.NewDecoder(r.GetBody())
+	if s.StrictJSON {
+		decoder.DisallowUnknownFields()
+	}
+	err := decoder.Decode(i)
+	if err != nil && s.StrictJSON {
+		// encoding/json does not export a typed error for unknown fields
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			if unquoted, uerr := strconv.Unquote(field); uerr == nil {
+				field = unquoted
+			}
+			return &UnknownFieldError{Source: "json", Fields: []string{field}}
+		}
+	}
+	return err
 }
 
 type XMLSerializer interface {
@@ -72,6 +255,13 @@ type Binder interface {
 	BindHeaders(r BindableRequest, i interface{}) error
 }
 
+// Bindable is the interface implemented by destinations binding themselves from the request: Bind delegates
+// to BindRequest, between the BeforeBind and AfterBind hooks, passing the binder so the type can run its single
+// source methods (BindBody...). BindRequest must not call b.Bind with its own receiver, which would delegate back to it.
+type Bindable interface {
+	BindRequest(r BindableRequest, b Binder) error
+}
+
 // BindUnmarshaler is the interface used to wrap the UnmarshalParam method.
 // Types that don't implement this, but do implement encoding.TextUnmarshaler
 // will use that interface instead.
@@ -80,6 +270,21 @@ type BindUnmarshaler interface {
 	UnmarshalParam(param string) error
 }
 
+// BindKeyUnmarshaler is the interface used to wrap the UnmarshalParamWithKey method.
+// Unlike BindUnmarshaler it receives the key as found in the request (which can differ in case
+// from the tag) and all the repeated values. It takes precedence over the other unmarshalers.
+type BindKeyUnmarshaler interface {
+	// UnmarshalParamWithKey decodes and assigns the values of the key from a form, query, path param or header.
+	UnmarshalParamWithKey(key string, values []string) error
+}
+
+// BindContextUnmarshaler is the interface used to wrap the UnmarshalParamContext method.
+// It allows tenant-aware or locale-aware parsing using request-scoped data.
+type BindContextUnmarshaler interface {
+	// UnmarshalParamContext decodes and assigns a value from a form or query param using the request context.
+	UnmarshalParamContext(ctx context.Context, param string) error
+}
+
 // bindMultipleUnmarshaler is used by binder to unmarshal multiple values from request at once to
 // type implementing this interface. For example request could have multiple query fields `?a=1&a=2&b=test` in that case
 // for `a` following slice `["1", "2"] will be passed to unmarshaller.
@@ -87,16 +292,38 @@ type bindMultipleUnmarshaler interface {
 	UnmarshalParams(params []string) error
 }
 
+// DefaultBinderInstance is the binder of the package level functions, created on first use.
+// Assigning it is only safe before serving requests; use SetDefaultBinder otherwise.
 var DefaultBinderInstance Binder
 
+// defaultBinderMu guards DefaultBinderInstance and DefaultHttpBinder.
+var defaultBinderMu sync.RWMutex
+
 // Returns the default binder instance.
 func GetBinder() Binder {
+	defaultBinderMu.RLock()
+	b := DefaultBinderInstance
+	defaultBinderMu.RUnlock()
+	if b != nil {
+		return b
+	}
+	defaultBinderMu.Lock()
+	defer defaultBinderMu.Unlock()
 	if DefaultBinderInstance == nil {
 		DefaultBinderInstance = NewBinder()
 	}
 	return DefaultBinderInstance
 }
 
+// SetDefaultBinder replaces the binder of the package level functions, including the BindHttp ones,
+// i.e. with a configured binder at startup. It is safe to call while requests are bound.
+func SetDefaultBinder(b Binder) {
+	defaultBinderMu.Lock()
+	defer defaultBinderMu.Unlock()
+	DefaultBinderInstance = b
+	DefaultHttpBinder = &HttpBinder{Binder: b}
+}
+
 func Bind(r BindableRequest, i interface{}) error {
 	return GetBinder().Bind(r, i)
 }
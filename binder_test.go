@@ -2,12 +2,32 @@ package binder_test
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/mail"
+	"net/netip"
 	"net/url"
+	"reflect"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	"github.com/gobigbang/binder"
 )
@@ -146,3 +166,3935 @@ func TestBindBody(t *testing.T) {
 		}
 	})
 }
+
+type FloatStruct struct {
+	Value float64 `query:"value"`
+}
+
+func TestBindFloatPolicy(t *testing.T) {
+	for _, value := range []string{"1e9", "Inf", "NaN", "-inf"} {
+		req := httptest.NewRequest(http.MethodGet, "/?value="+value, nil)
+		var data FloatStruct
+		if err := binder.BindHttpQueryParams(req, &data); err == nil {
+			t.Fatalf("expected error for %q, got %+v", value, data)
+		}
+	}
+
+	b := binder.NewBinder()
+	b.AllowFloatExponent = true
+	b.AllowFloatNonFinite = true
+	httpBinder := &binder.HttpBinder{Binder: b}
+	for _, value := range []string{"1e9", "Inf", "NaN", "1.5"} {
+		req := httptest.NewRequest(http.MethodGet, "/?value="+value, nil)
+		var data FloatStruct
+		if err := httpBinder.BindQueryParams(req, &data); err != nil {
+			t.Fatalf("expected no error for %q, got %v", value, err)
+		}
+	}
+}
+
+type IDStruct struct {
+	ID int64 `query:"id" convert:"id"`
+}
+
+func TestBindIDConverter(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?id=42", nil)
+	var data IDStruct
+	if err := binder.BindHttpQueryParams(req, &data); err != nil || data.ID != 42 {
+		t.Fatalf("expected id 42, got %+v (err %v)", data, err)
+	}
+
+	for _, value := range []string{"0", "-1", "abc"} {
+		req := httptest.NewRequest(http.MethodGet, "/?id="+value, nil)
+		var data IDStruct
+		if err := binder.BindHttpQueryParams(req, &data); !errors.Is(err, binder.ErrInvalidID) {
+			t.Fatalf("expected ErrInvalidID for %q, got %v", value, err)
+		}
+	}
+}
+
+func TestBindStrictJSON(t *testing.T) {
+	b := binder.NewBinder()
+	b.JSONSerializer = binder.DefaultJSONSerializer{StrictJSON: true}
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	body := `{"name":"John Doe","emial":"john@example.com"}`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	var data TestStruct
+	err := httpBinder.BindBody(req, &data)
+	var unknown *binder.UnknownFieldError
+	if !errors.As(err, &unknown) || len(unknown.Fields) != 1 || unknown.Fields[0] != "emial" {
+		t.Fatalf("expected unknown field error for emial, got %v", err)
+	}
+}
+
+func TestBindStrictKeys(t *testing.T) {
+	b := binder.NewBinder()
+	b.StrictKeys = true
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	form := url.Values{}
+	form.Add("parent_name", "Jane Doe")
+	form.Add("child.name", "John Doe")
+	form.Add("child[age]", "10")
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	var data NestedStruct
+	if err := httpBinder.BindBody(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	form.Add("child.nmae", "typo")
+	form.Add("prent_name", "typo")
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	err := httpBinder.BindBody(req, &data)
+	var unknown *binder.UnknownFieldError
+	if !errors.As(err, &unknown) || strings.Join(unknown.Fields, ",") != "child.nmae,prent_name" {
+		t.Fatalf("expected unknown field error, got %v", err)
+	}
+}
+
+func TestBindStrictKeysIgnoresCase(t *testing.T) {
+	b := binder.NewBinder()
+	b.StrictKeys = true
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("Parent_Name=Jane+Doe&CHILD.name=John+Doe&Child[age]=10"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	var data NestedStruct
+	if err := httpBinder.BindBody(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.ParentName != "Jane Doe" {
+		t.Fatalf("expected the parent name to be bound, got %+v", data)
+	}
+
+	b.CompatLevel = binder.CompatV2
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("CHILD.name=John+Doe"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	var unknown *binder.UnknownFieldError
+	if err := httpBinder.BindBody(req, &data); !errors.As(err, &unknown) || strings.Join(unknown.Fields, ",") != "CHILD.name" {
+		t.Fatalf("expected unknown field error with exact keys, got %v", err)
+	}
+}
+
+func TestBindExactKeysCompatV2(t *testing.T) {
+	type Account struct {
+		Name  string `query:"name"`
+		Owner string `query:"owner" bind:",readonly"`
+	}
+	b := binder.NewBinder()
+	b.CompatLevel = binder.CompatV2
+	b.StrictKeys = true
+	b.ReadOnlyPolicy = binder.ReadOnlyError
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	var unknown *binder.UnknownFieldError
+	req := httptest.NewRequest(http.MethodGet, "/?NAME=John", nil)
+	if err := httpBinder.BindQueryParams(req, &Account{}); !errors.As(err, &unknown) || strings.Join(unknown.Fields, ",") != "NAME" {
+		t.Fatalf("expected an unknown field error for a key of another case, got %v", err)
+	}
+	b.StrictKeys = false
+	req = httptest.NewRequest(http.MethodGet, "/?name=John&OWNER=mallory", nil)
+	data := Account{Owner: "alice"}
+	if err := httpBinder.BindQueryParams(req, &data); err != nil || data != (Account{Name: "John", Owner: "alice"}) {
+		t.Fatalf("expected a key of another case not to supply the read-only field, got %+v, %v", data, err)
+	}
+}
+
+type TimeStruct struct {
+	Default time.Time   `query:"default"`
+	Date    time.Time   `query:"date" layout:"2006-01-02"`
+	Option  *time.Time  `query:"option,layout=02/01/2006"`
+	Unix    time.Time   `query:"unix" layout:"unix"`
+	Milli   time.Time   `query:"milli,layout=unixmilli"`
+	Times   []time.Time `query:"times" layout:"2006-01-02"`
+}
+
+func TestBindTime(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?default=2024-05-01T10:00:00Z&date=2024-05-01&option=01/05/2024&unix=1714557600&milli=1714557600000&times=2024-05-01&times=2024-05-02", nil)
+	var data TimeStruct
+	if err := binder.BindHttpQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	date := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	if !data.Default.Equal(expected) || !data.Date.Equal(date) || data.Option == nil || !data.Option.Equal(date) ||
+		!data.Unix.Equal(expected) || !data.Milli.Equal(expected) || len(data.Times) != 2 || !data.Times[1].Equal(date.AddDate(0, 0, 1)) {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?date=01/05/2024", nil)
+	if err := binder.BindHttpQueryParams(req, &data); err == nil {
+		t.Fatalf("expected layout error, got nil")
+	}
+}
+
+type TimeZoneStruct struct {
+	Local time.Time `query:"local" layout:"2006-01-02 15:04" tz:"America/New_York"`
+	UTC   time.Time `query:"utc,layout=2006-01-02 15:04"`
+}
+
+func TestBindTimeZone(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?local=2024-05-01+10:00&utc=2024-05-01+10:00", nil)
+	var data TimeZoneStruct
+	if err := binder.BindHttpQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Local.Location().String() != "America/New_York" || !data.Local.Equal(time.Date(2024, 5, 1, 14, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected local time in America/New_York, got %v", data.Local)
+	}
+	if data.UTC.Location() != time.UTC || data.UTC.Hour() != 10 {
+		t.Fatalf("expected utc time, got %v", data.UTC)
+	}
+}
+
+type UnitsStruct struct {
+	Upload   binder.ByteSize `query:"upload"`
+	Cache    binder.ByteSize `query:"cache"`
+	Requests binder.Rate     `query:"requests"`
+	Burst    binder.Rate     `query:"burst"`
+}
+
+func TestBindUnits(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?upload=10MB&cache=512KiB&requests=100rps&burst=120/m", nil)
+	var data UnitsStruct
+	if err := binder.BindHttpQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Upload != 10_000_000 || data.Cache != 512*1024 || data.Requests != 100 || data.Burst != 2 {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?upload=10XB", nil)
+	if err := binder.BindHttpQueryParams(req, &data); err == nil {
+		t.Fatalf("expected unit error, got nil")
+	}
+}
+
+type NullStruct struct {
+	Name    sql.NullString  `query:"name"`
+	Age     sql.NullInt64   `query:"age"`
+	Active  sql.NullBool    `query:"active"`
+	Since   sql.NullTime    `query:"since" layout:"2006-01-02"`
+	Score   *sql.NullInt32  `query:"score"`
+	Missing sql.NullFloat64 `query:"missing"`
+}
+
+func TestBindSQLNullTypes(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?name=John&age=30&active=true&since=2024-05-01&score=", nil)
+	var data NullStruct
+	if err := binder.BindHttpQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !data.Name.Valid || data.Name.String != "John" || !data.Age.Valid || data.Age.Int64 != 30 || !data.Active.Valid || !data.Active.Bool ||
+		!data.Since.Valid || data.Since.Time.Day() != 1 || data.Score == nil || data.Score.Valid || data.Missing.Valid {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?age=abc", nil)
+	if err := binder.BindHttpQueryParams(req, &data); err == nil {
+		t.Fatalf("expected scan error, got nil")
+	}
+}
+
+type NetStruct struct {
+	ClientIP netip.Addr   `query:"client_ip"`
+	Subnet   netip.Prefix `query:"subnet"`
+	IP       net.IP       `query:"ip"`
+	CIDR     *net.IPNet   `query:"cidr"`
+	IPs      []net.IP     `query:"ips"`
+}
+
+func TestBindNet(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?client_ip=10.0.0.1&subnet=192.168.0.0/16&ip=::1&cidr=10.1.2.3/8&ips=1.1.1.1&ips=8.8.8.8", nil)
+	var data NetStruct
+	if err := binder.BindHttpQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.ClientIP != netip.MustParseAddr("10.0.0.1") || data.Subnet != netip.MustParsePrefix("192.168.0.0/16") || !data.IP.Equal(net.IPv6loopback) ||
+		data.CIDR == nil || data.CIDR.String() != "10.0.0.0/8" || len(data.IPs) != 2 || data.IPs[1].String() != "8.8.8.8" {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?cidr=10.0.0.1", nil)
+	if err := binder.BindHttpQueryParams(req, &data); !errors.Is(err, binder.ErrInvalidCIDR) {
+		t.Fatalf("expected ErrInvalidCIDR, got %v", err)
+	}
+}
+
+type AddressStruct struct {
+	Callback *url.URL      `query:"callback"`
+	Homepage url.URL       `query:"homepage"`
+	Email    mail.Address  `query:"email"`
+	CC       *mail.Address `query:"cc"`
+}
+
+func TestBindURLAndMailAddress(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?callback=https://example.com/hook?a=1&homepage=http://joe.dev&email=Joe+%3Cjoe@example.com%3E&cc=ann@example.com", nil)
+	var data AddressStruct
+	if err := binder.BindHttpQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Callback == nil || data.Callback.Host != "example.com" || data.Callback.Path != "/hook" || data.Homepage.Host != "joe.dev" ||
+		data.Email.Name != "Joe" || data.Email.Address != "joe@example.com" || data.CC == nil || data.CC.Address != "ann@example.com" {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+
+	for query, typ := range map[string]reflect.Type{
+		"callback=/relative":    reflect.TypeOf(url.URL{}),
+		"homepage=http://a%20b": reflect.TypeOf(url.URL{}),
+		"email=not-an-address":  reflect.TypeOf(mail.Address{}),
+		"cc=joe@example.com%3E": reflect.TypeOf(mail.Address{}),
+	} {
+		var parseErr *binder.ParseError
+		err := binder.BindHttpQueryParams(httptest.NewRequest(http.MethodGet, "/?"+query, nil), &AddressStruct{})
+		if !errors.As(err, &parseErr) || parseErr.Type != typ {
+			t.Fatalf("expected a ParseError of %s for %s, got %v", typ, query, err)
+		}
+	}
+	err := binder.BindHttpQueryParams(httptest.NewRequest(http.MethodGet, "/?callback=example.com", nil), &AddressStruct{})
+	if !errors.Is(err, binder.ErrRelativeURL) {
+		t.Fatalf("expected ErrRelativeURL, got %v", err)
+	}
+}
+
+type KeyedParam struct {
+	Key    string
+	Values []string
+}
+
+func (p *KeyedParam) UnmarshalParamWithKey(key string, values []string) error {
+	p.Key = key
+	p.Values = values
+	return nil
+}
+
+type KeyedStruct struct {
+	Filter KeyedParam `query:"filter"`
+}
+
+func TestBindKeyUnmarshaler(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?Filter=a&Filter=b", nil)
+	var data KeyedStruct
+	if err := binder.BindHttpQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Filter.Key != "Filter" || strings.Join(data.Filter.Values, ",") != "a,b" {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+}
+
+type tenantKey struct{}
+
+type TenantParam string
+
+func (p *TenantParam) UnmarshalParamContext(ctx context.Context, param string) error {
+	*p = TenantParam(ctx.Value(tenantKey{}).(string) + ":" + param)
+	return nil
+}
+
+type TenantStruct struct {
+	Code TenantParam `query:"code"`
+}
+
+func TestBindContextUnmarshaler(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?code=42", nil)
+	req = req.WithContext(context.WithValue(req.Context(), tenantKey{}, "acme"))
+	var data TenantStruct
+	if err := binder.BindHttpQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Code != "acme:42" {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+}
+
+type BigStruct struct {
+	Int   *big.Int   `query:"int"`
+	Float *big.Float `query:"float,prec=128"`
+}
+
+func TestBindBigNumbers(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?int=123456789012345678901234567890&float=0.1234567890123456789012345", nil)
+	var data BigStruct
+	if err := binder.BindHttpQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Int == nil || data.Int.String() != "123456789012345678901234567890" || data.Float == nil ||
+		data.Float.Prec() != 128 || data.Float.Text('f', 25) != "0.1234567890123456789012345" {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?float=Inf", nil)
+	if err := binder.BindHttpQueryParams(req, &data); !errors.Is(err, binder.ErrFloatNonFinite) {
+		t.Fatalf("expected ErrFloatNonFinite, got %v", err)
+	}
+}
+
+type ByteSizeTagStruct struct {
+	Limit int64  `query:"limit,bytesize"`
+	Quota uint64 `query:"quota" convert:"bytesize"`
+}
+
+func TestBindByteSizeOption(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?limit=10MB&quota=1GiB", nil)
+	var data ByteSizeTagStruct
+	if err := binder.BindHttpQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Limit != 10_000_000 || data.Quota != 1<<30 {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+}
+
+type Money struct {
+	Amount   string
+	Currency string
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	amount, currency, ok := strings.Cut(s, " ")
+	if !ok {
+		return errors.New("invalid money")
+	}
+	m.Amount, m.Currency = amount, currency
+	return nil
+}
+
+type OrderStruct struct {
+	Price Money  `json:"price" query:"price"`
+	Total *Money `json:"total" query:"total"`
+}
+
+func TestBindJSONUnmarshalerMerge(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/?price=10.50+EUR&total=12.00+EUR", strings.NewReader(`{"price":"9.90 USD"}`))
+	req.Header.Set("Content-Type", "application/json")
+	var data OrderStruct
+	if err := binder.BindHttp(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// the body is bound last and goes through UnmarshalJSON like the query values
+	if data.Price != (Money{"9.90", "USD"}) || data.Total == nil || *data.Total != (Money{"12.00", "EUR"}) {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?price=invalid", nil)
+	if err := binder.BindHttpQueryParams(req, &data); err == nil {
+		t.Fatalf("expected UnmarshalJSON error, got nil")
+	}
+}
+
+type JSONString string
+
+func (s *JSONString) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = JSONString("json:" + value)
+	return nil
+}
+
+func TestBindJSONUnmarshalerQuotesScalars(t *testing.T) {
+	type Request struct {
+		Value JSONString  `query:"value"`
+		Ptr   *JSONString `query:"ptr"`
+	}
+	for _, value := range []string{"123", "null", "true", `"quoted"`} {
+		req := httptest.NewRequest(http.MethodGet, "/?value="+url.QueryEscape(value)+"&ptr="+url.QueryEscape(value), nil)
+		var data Request
+		if err := binder.BindHttpQueryParams(req, &data); err != nil {
+			t.Fatalf("expected %s to be bound as a string, got %v", value, err)
+		}
+		if data.Value != JSONString("json:"+value) || data.Ptr == nil || *data.Ptr != data.Value {
+			t.Fatalf("expected %s to be passed quoted, got %+v", value, data)
+		}
+	}
+
+	var raw struct {
+		Value JSONString `query:"value,json"`
+	}
+	req := httptest.NewRequest(http.MethodGet, "/?value=%22quoted%22", nil)
+	if err := binder.BindHttpQueryParams(req, &raw); err != nil || raw.Value != "json:quoted" {
+		t.Fatalf("expected the json option to pass the raw value, got %+v, %v", raw, err)
+	}
+}
+
+type Status int
+
+const (
+	StatusActive Status = iota + 1
+	StatusSuspended
+)
+
+func (Status) BindEnum() map[string]interface{} {
+	return map[string]interface{}{"active": StatusActive, "suspended": StatusSuspended}
+}
+
+type Plan string
+
+type EnumStruct struct {
+	Status Status  `query:"status"`
+	Plan   *Plan   `query:"plan"`
+	Plans  []Plan  `query:"plans"`
+	Other  *Status `query:"other"`
+}
+
+func TestBindEnum(t *testing.T) {
+	b := binder.NewBinder()
+	b.RegisterEnum(reflect.TypeOf(Plan("")), map[string]interface{}{"free": "FREE", "pro": "PRO"})
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	req := httptest.NewRequest(http.MethodGet, "/?status=suspended&plan=pro&plans=free&plans=pro&other=active", nil)
+	var data EnumStruct
+	if err := httpBinder.BindQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Status != StatusSuspended || data.Plan == nil || *data.Plan != "PRO" || len(data.Plans) != 2 || data.Plans[0] != "FREE" ||
+		data.Other == nil || *data.Other != StatusActive {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?status=deleted", nil)
+	err := httpBinder.BindQueryParams(req, &data)
+	var enumErr *binder.EnumError
+	if !errors.As(err, &enumErr) || strings.Join(enumErr.Allowed, ",") != "active,suspended" {
+		t.Fatalf("expected enum error, got %v", err)
+	}
+}
+
+type mediaTypeSerializer struct {
+	mediaType string
+	params    map[string]string
+}
+
+func (s *mediaTypeSerializer) Deserialize(r binder.BindableRequest, i interface{}) error {
+	s.mediaType, s.params = binder.GetMediaType(r)
+	return binder.DefaultJSONSerializer{}.Deserialize(r, i)
+}
+
+func TestBindBodyMediaType(t *testing.T) {
+	serializer := &mediaTypeSerializer{}
+	b := binder.NewBinder()
+	b.JSONSerializer = serializer
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"John Doe"}`))
+	req.Header.Set("Content-Type", "Application/JSON; charset=UTF-8; version=2")
+	var data TestStruct
+	if err := httpBinder.BindBody(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Name != "John Doe" || serializer.mediaType != binder.MIMEApplicationJSON || serializer.params["charset"] != "UTF-8" || serializer.params["version"] != "2" {
+		t.Fatalf("expected media type to be parsed, got %q %v", serializer.mediaType, serializer.params)
+	}
+}
+
+type CheckboxStruct struct {
+	Newsletter bool   `form:"newsletter"`
+	Terms      bool   `form:"terms"`
+	Name       string `form:"name"`
+}
+
+func TestBindLenientBool(t *testing.T) {
+	b := binder.NewBinder()
+	b.LenientBool = true
+	b.FormCheckboxes = true
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	form := url.Values{}
+	form.Add("newsletter", "on")
+	form.Add("name", "John Doe")
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	data := CheckboxStruct{Terms: true}
+	if err := httpBinder.BindBody(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !data.Newsletter || data.Terms {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("newsletter=on"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if err := binder.BindHttpBody(req, &data); err == nil {
+		t.Fatalf("expected error with strict bool parsing, got nil")
+	}
+}
+
+type versionedDeserializer struct{}
+
+func (versionedDeserializer) Deserialize(r binder.BindableRequest, i interface{}) error {
+	return binder.DefaultJSONSerializer{}.Deserialize(r, i)
+}
+
+func (d versionedDeserializer) DeserializeMediaType(r binder.BindableRequest, mediaType string, params map[string]string, i interface{}) error {
+	if err := d.Deserialize(r, i); err != nil {
+		return err
+	}
+	if data, ok := i.(*TestStruct); ok && params["version"] == "1" {
+		data.Email = "legacy"
+	}
+	return nil
+}
+
+func TestBindBodyVendorMediaType(t *testing.T) {
+	b := binder.NewBinder()
+	b.RegisterDeserializer("application/vnd.acme+json", versionedDeserializer{})
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"John Doe"}`))
+	req.Header.Set("Content-Type", "application/vnd.acme+json; version=1")
+	var data TestStruct
+	if err := httpBinder.BindBody(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Name != "John Doe" || data.Email != "legacy" {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+}
+
+type PayloadV1 struct {
+	Name string `json:"name"`
+}
+
+type PayloadV2 struct {
+	FirstName string `json:"first_name"`
+}
+
+func TestBindCandidates(t *testing.T) {
+	candidates := []binder.Candidate{
+		{MediaType: binder.MIMEApplicationJSON, Version: "2", New: func() interface{} { return &PayloadV2{} }},
+		{MediaType: binder.MIMEApplicationJSON, New: func() interface{} { return &PayloadV1{} }},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"first_name":"John"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Api-Version", "2")
+	i, data, err := binder.BindHttpCandidates(req, "X-Api-Version", candidates...)
+	if err != nil || i != 0 || data.(*PayloadV2).FirstName != "John" {
+		t.Fatalf("expected v2 payload, got %d %+v (err %v)", i, data, err)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"John"}`))
+	req.Header.Set("Content-Type", "application/json")
+	i, data, err = binder.BindHttpCandidates(req, "X-Api-Version", candidates...)
+	if err != nil || i != 1 || data.(*PayloadV1).Name != "John" {
+		t.Fatalf("expected v1 payload, got %d %+v (err %v)", i, data, err)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`<name>John</name>`))
+	req.Header.Set("Content-Type", "application/xml")
+	if _, _, err := binder.BindHttpCandidates(req, "", candidates...); !errors.Is(err, binder.ErrNoCandidate) {
+		t.Fatalf("expected ErrNoCandidate, got %v", err)
+	}
+}
+
+func TestBindCandidatesVersionHeaderCase(t *testing.T) {
+	candidates := []binder.Candidate{
+		{MediaType: binder.MIMEApplicationJSON, Version: "2", New: func() interface{} { return &PayloadV2{} }},
+		{MediaType: binder.MIMEApplicationJSON, New: func() interface{} { return &PayloadV1{} }},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"first_name":"John"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header["x-api-version"] = []string{"2"}
+	i, data, err := binder.BindHttpCandidates(req, "X-API-VERSION", candidates...)
+	if err != nil || i != 0 || data.(*PayloadV2).FirstName != "John" {
+		t.Fatalf("expected v2 payload, got %d %+v (err %v)", i, data, err)
+	}
+}
+
+type CheckboxOptionStruct struct {
+	Terms     bool  `form:"terms,checkbox"`
+	Marketing *bool `form:"marketing,checkbox"`
+	Other     bool  `form:"other"`
+}
+
+func TestBindCheckboxOption(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("name=John"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	data := CheckboxOptionStruct{Terms: true, Other: true}
+	if err := binder.BindHttpBody(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Terms || data.Marketing == nil || *data.Marketing || !data.Other {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+}
+
+func TestBindCheckboxNestedStructs(t *testing.T) {
+	type Preferences struct {
+		Newsletter bool `form:"newsletter,checkbox"`
+	}
+	type Account struct {
+		Name     string       `form:"name"`
+		Settings Preferences  `form:"settings"`
+		Prefs    *Preferences `form:"prefs"`
+		Unset    *Preferences `form:"unset"`
+	}
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("name=John"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	data := Account{Settings: Preferences{Newsletter: true}, Prefs: &Preferences{Newsletter: true}}
+	if err := binder.BindHttpBody(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Settings.Newsletter || data.Prefs == nil || data.Prefs.Newsletter || data.Unset != nil {
+		t.Fatalf("expected the checkboxes of value and pointer structs to be reset, got %+v", data)
+	}
+}
+
+func TestBindCheckboxEmptyForm(t *testing.T) {
+	type Preferences struct {
+		Newsletter bool `form:"newsletter"`
+	}
+	type Account struct {
+		Terms bool         `form:"terms,checkbox"`
+		Prefs *Preferences `form:"prefs"`
+	}
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(""))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	data := CheckboxOptionStruct{Terms: true, Other: true}
+	if err := binder.BindHttpBody(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Terms || data.Marketing == nil || *data.Marketing || !data.Other {
+		t.Fatalf("expected the checkboxes of an empty form to be reset, got %+v", data)
+	}
+
+	b := binder.NewBinder()
+	b.FormCheckboxes = true
+	httpBinder := &binder.HttpBinder{Binder: b}
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(""))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	account := Account{Terms: true, Prefs: &Preferences{Newsletter: true}}
+	if err := httpBinder.BindBody(req, &account); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if account.Terms || account.Prefs == nil || account.Prefs.Newsletter {
+		t.Fatalf("expected the form checkboxes of an empty form to be reset, got %+v", account)
+	}
+}
+
+type HeaderStruct struct {
+	ForwardedFor []string `header:"X-Forwarded-For"`
+	RequestID    string   `header:"x-request-id"`
+}
+
+func TestBindHeadersMultiValue(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Add("X-Forwarded-For", "10.0.0.1, 10.0.0.2")
+	req.Header.Add("X-Forwarded-For", "10.0.0.3")
+	req.Header.Set("X-Request-Id", "abc")
+
+	var data HeaderStruct
+	if err := binder.BindHttpHeaders(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(data.ForwardedFor) != 2 || data.ForwardedFor[1] != "10.0.0.3" || data.RequestID != "abc" {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+
+	b := binder.NewBinder()
+	b.SplitHeaderValues = true
+	httpBinder := &binder.HttpBinder{Binder: b}
+	data = HeaderStruct{}
+	if err := httpBinder.BindHeaders(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if strings.Join(data.ForwardedFor, ",") != "10.0.0.1,10.0.0.2,10.0.0.3" {
+		t.Fatalf("expected split values, got %+v", data)
+	}
+}
+
+type NormalizeStruct struct {
+	Email string   `query:"email,trim,lower"`
+	Code  string   `query:"code,upper"`
+	Name  string   `query:"name"`
+	Tags  []string `query:"tags,trim"`
+}
+
+func TestBindNormalize(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?email=+John@Example.COM+&code=abc&name=+John+&tags=+a&tags=b+", nil)
+	var data NormalizeStruct
+	if err := binder.BindHttpQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Email != "john@example.com" || data.Code != "ABC" || data.Name != " John " || strings.Join(data.Tags, ",") != "a,b" {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+
+	b := binder.NewBinder()
+	b.TrimStrings = true
+	httpBinder := &binder.HttpBinder{Binder: b}
+	if err := httpBinder.BindQueryParams(req, &data); err != nil || data.Name != "John" {
+		t.Fatalf("expected trimmed name, got %+v (err %v)", data, err)
+	}
+}
+
+func TestBindNormalizeSliceElements(t *testing.T) {
+	var data struct {
+		Codes  []string  `query:"codes,trim,upper"`
+		Emails *[]string `query:"emails,lower"`
+	}
+	req := httptest.NewRequest(http.MethodGet, "/?codes[0]=+ab+&codes[1]=Cd&emails.0=John@Example.COM", nil)
+	if err := binder.BindHttpQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if strings.Join(data.Codes, ",") != "AB,CD" || data.Emails == nil || strings.Join(*data.Emails, ",") != "john@example.com" {
+		t.Fatalf("expected normalized elements, got %+v", data)
+	}
+}
+
+type SplitStruct struct {
+	IDs    []int     `query:"ids,split"`
+	Tags   *[]string `query:"tags,delim=pipe"`
+	Words  []string  `query:"words,delim=space"`
+	Codes  []string  `query:"codes" delim:":"`
+	Addrs  []net.IP  `query:"addrs,split"`
+	Single string    `query:"single,split"`
+}
+
+func TestBindSplit(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?ids=1,2,3&ids=4&tags=a|b&words=x+y&codes=c1:c2&addrs=1.1.1.1,8.8.8.8&single=a,b", nil)
+	var data SplitStruct
+	if err := binder.BindHttpQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(data.IDs) != 4 || data.IDs[2] != 3 || data.Tags == nil || strings.Join(*data.Tags, ",") != "a,b" ||
+		strings.Join(data.Words, ",") != "x,y" || strings.Join(data.Codes, ",") != "c1,c2" || len(data.Addrs) != 2 || data.Single != "a,b" {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+}
+
+type URLStruct struct {
+	Host     string `request:"host"`
+	Hostname string `request:"hostname"`
+	Port     int    `request:"port"`
+	Scheme   string `request:"scheme"`
+	Path     string `request:"escaped_path"`
+	RawQuery string `request:"raw_query"`
+	URL      string `request:"url"`
+	Name     string `query:"name"`
+}
+
+func TestBindRequestMetadata(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://example.com:8080/a%20b?name=John", nil)
+	var data URLStruct
+	if err := binder.NewHttpBinder().BindWith(req, &data, binder.WithBindRequestMetadata(true)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Host != "example.com:8080" || data.Hostname != "example.com" || data.Port != 8080 || data.Scheme != "http" ||
+		data.Path != "/a%20b" || data.RawQuery != "name=John" || data.URL != "http://example.com:8080/a%20b?name=John" || data.Name != "John" {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+
+	values := map[string]string{}
+	if err := binder.BindHttp(req, &values); err != nil || len(values) != 1 {
+		t.Fatalf("expected only query values in map, got %v (err %v)", values, err)
+	}
+}
+
+func TestWithBindRequestMetadata(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://example.com:8080/?name=John", nil)
+	var data URLStruct
+	if err := binder.BindHttp(req, &data); err != nil || data != (URLStruct{Name: "John"}) {
+		t.Fatalf("expected the request metadata to be opt-in, got %+v (err %v)", data, err)
+	}
+
+	httpBinder := binder.NewHttpBinder()
+	if err := httpBinder.BindWith(req, &data, binder.WithBindRequestMetadata(true)); err != nil || data.Hostname != "example.com" || data.Name != "John" {
+		t.Fatalf("expected the request metadata to be bound, got %+v (err %v)", data, err)
+	}
+	data = URLStruct{}
+	if err := httpBinder.Bind(req, &data); err != nil || data != (URLStruct{Name: "John"}) {
+		t.Fatalf("expected the binder to be left untouched, got %+v (err %v)", data, err)
+	}
+
+	b := binder.NewBinder()
+	binder.WithBindRequestMetadata(true)(b)
+	binder.WithBindRequestMetadata(true)(b)
+	steps := len(b.BindOrder)
+	binder.WithBindRequestMetadata(false)(b)
+	if len(b.BindOrder) != steps-1 {
+		t.Fatalf("expected the step to be added once and removed, got %d then %d steps", steps, len(b.BindOrder))
+	}
+}
+
+type ClientIPStruct struct {
+	ClientIP   netip.Addr `request:"client_ip"`
+	RemoteAddr string     `request:"remote_addr"`
+}
+
+func TestBindClientIP(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.2:1234"
+	req.Header.Add("X-Forwarded-For", "1.2.3.4, 203.0.113.7, 10.0.0.1")
+
+	var data ClientIPStruct
+	if err := binder.BindHttpRequestMetadata(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.ClientIP != netip.MustParseAddr("10.0.0.2") || data.RemoteAddr != "10.0.0.2:1234" {
+		t.Fatalf("expected forwarding headers to be ignored, got %+v", data)
+	}
+
+	resolver, err := binder.NewClientIPResolver("10.0.0.0/8")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	b := binder.NewBinder()
+	b.ClientIPResolver = resolver
+	httpBinder := &binder.HttpBinder{Binder: b}
+	if err := httpBinder.BindRequestMetadata(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// 1.2.3.4 could be spoofed by the client, the first untrusted hop is the client
+	if data.ClientIP != netip.MustParseAddr("203.0.113.7") {
+		t.Fatalf("expected client ip from the trusted chain, got %+v", data)
+	}
+
+	resolver.UseForwarded = true
+	req.Header.Set("Forwarded", `for=198.51.100.17;proto=https, for="[2001:db8::1]:4711"`)
+	if err := httpBinder.BindRequestMetadata(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.ClientIP != netip.MustParseAddr("2001:db8::1") {
+		t.Fatalf("expected client ip from the Forwarded header, got %+v", data)
+	}
+}
+
+type PeerStruct struct {
+	Method     string     `request:"method"`
+	Proto      string     `request:"proto"`
+	RemoteIP   netip.Addr `request:"remote_ip"`
+	RemoteAddr string     `request:"remote_addr"`
+}
+
+// tunneledRequest reports the peer of a tunnel instead of the address of the connection.
+type tunneledRequest struct {
+	binder.HttpBindableRequest
+}
+
+func (r tunneledRequest) GetProto() string {
+	return "HTTP/3.0"
+}
+
+func (r tunneledRequest) GetRemoteAddr() string {
+	return "[2001:db8::2]:443"
+}
+
+func TestBindRequestPeer(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPut, "/", nil)
+	req.RemoteAddr = "10.0.0.2:1234"
+	req.Header.Add("X-Forwarded-For", "203.0.113.7")
+
+	var data PeerStruct
+	if err := binder.BindHttpRequestMetadata(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Method != http.MethodPut || data.Proto != "HTTP/1.1" || data.RemoteIP != netip.MustParseAddr("10.0.0.2") || data.RemoteAddr != "10.0.0.2:1234" {
+		t.Fatalf("expected the peer of the request, got %+v", data)
+	}
+
+	data = PeerStruct{}
+	b := binder.NewBinder()
+	if err := b.BindRequestMetadata(tunneledRequest{binder.NewHttpBindableRequest(req)}, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Proto != "HTTP/3.0" || data.RemoteIP != netip.MustParseAddr("2001:db8::2") || data.RemoteAddr != "[2001:db8::2]:443" {
+		t.Fatalf("expected the peer of the bindable request, got %+v", data)
+	}
+}
+
+type DeepObjectFilter struct {
+	Name  string `query:"name"`
+	Age   int    `query:"age"`
+	Range struct {
+		Min int `query:"min"`
+	} `query:"range"`
+}
+
+type DeepObjectStruct struct {
+	Filter   DeepObjectFilter  `query:"filter"`
+	Filters  *DeepObjectFilter `query:"filters"`
+	Sort     map[string]string `query:"sort"`
+	FilterBy string            `query:"filter_by"`
+}
+
+func TestBindDeepObjectQuery(t *testing.T) {
+	query := url.Values{}
+	query.Add("filter[name]", "x")
+	query.Add("filter[age]", "3")
+	query.Add("filter[range][min]", "1")
+	query.Add("filters[name]", "y")
+	query.Add("sort[created at]", "desc")
+	query.Add("filter_by", "z")
+	req := httptest.NewRequest(http.MethodGet, "/?"+query.Encode(), nil)
+
+	var data DeepObjectStruct
+	if err := binder.BindHttpQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Filter.Name != "x" || data.Filter.Age != 3 || data.Filter.Range.Min != 1 || data.Filters == nil || data.Filters.Name != "y" ||
+		data.Sort["created at"] != "desc" || len(data.Sort) != 1 || data.FilterBy != "z" {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+}
+
+func TestBindHeadersLimits(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for i := 0; i < 1000; i++ {
+		req.Header.Add("X-Many", "1")
+	}
+	if err := binder.BindHttpHeaders(req, &map[string]string{}); err != nil {
+		t.Fatalf("expected no header values limit by default, got %v", err)
+	}
+
+	b := binder.NewBinder()
+	b.MaxHeaderValues = 3
+	b.MaxHeaderBytes = 64
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-Id", "abc")
+	values := map[string]string{}
+	if err := httpBinder.BindHeaders(req, &values); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		req.Header.Add("X-Bomb", "1")
+	}
+	var limitErr *binder.LimitError
+	if err := httpBinder.BindHeaders(req, &values); !errors.As(err, &limitErr) || limitErr.Limit != "values" {
+		t.Fatalf("expected values limit error, got %v", err)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Bomb", strings.Repeat("x", 64))
+	if err := httpBinder.BindHeaders(req, &values); !errors.As(err, &limitErr) || limitErr.Limit != "bytes" {
+		t.Fatalf("expected bytes limit error, got %v", err)
+	}
+}
+
+type Item struct {
+	Name string `form:"name" query:"name"`
+	Qty  int    `form:"qty" query:"qty"`
+}
+
+type ItemsStruct struct {
+	Items    []Item    `form:"items" query:"items"`
+	Pointers []*Item   `form:"pointers" query:"pointers"`
+	Optional *[]Item   `form:"optional" query:"optional"`
+	Codes    *[]string `form:"codes" query:"codes"`
+}
+
+func TestBindSliceOfStructs(t *testing.T) {
+	form := url.Values{}
+	form.Add("items[0].name", "a")
+	form.Add("items[0].qty", "2")
+	form.Add("items[1][name]", "b")
+	form.Add("pointers.0.name", "c")
+	form.Add("optional[1].qty", "5")
+	form.Add("codes[0]", "x")
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var data ItemsStruct
+	if err := binder.BindHttpBody(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(data.Items) != 2 || data.Items[0] != (Item{"a", 2}) || data.Items[1] != (Item{"b", 0}) ||
+		len(data.Pointers) != 1 || *data.Pointers[0] != (Item{"c", 0}) ||
+		data.Optional == nil || len(*data.Optional) != 2 || (*data.Optional)[1].Qty != 5 ||
+		data.Codes == nil || len(*data.Codes) != 1 {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?"+form.Encode(), nil)
+	data = ItemsStruct{}
+	if err := binder.BindHttpQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(data.Items) != 2 || data.Items[0] != (Item{"a", 2}) {
+		t.Fatalf("expected query data to be bound correctly, got %+v", data)
+	}
+}
+
+func TestBindQueryNormalizers(t *testing.T) {
+	b := binder.NewBinder()
+	b.StrictKeys = true
+	b.RegisterQueryNormalizer(binder.StripQueryPrefixes("utm_"))
+	b.RegisterQueryNormalizer(binder.RenameQueryKeys(map[string]string{"fullname": "name"}))
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	req := httptest.NewRequest(http.MethodGet, "/?fullname=John&utm_source=newsletter&utm_medium=email", nil)
+	var data struct {
+		Name string `query:"name"`
+	}
+	if err := httpBinder.BindQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Name != "John" {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+}
+
+func TestBindSparseArrayPolicy(t *testing.T) {
+	type Item struct {
+		Name string `query:"name"`
+	}
+	type Request struct {
+		IDs   []int  `query:"ids"`
+		Items []Item `query:"items"`
+	}
+	url := "/?ids[0]=1&ids[5]=6&items[2][name]=b&items[0][name]=a"
+
+	var data Request
+	if err := binder.BindHttpQueryParams(httptest.NewRequest(http.MethodGet, url, nil), &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(data.IDs) != 6 || data.IDs[5] != 6 || len(data.Items) != 3 || data.Items[2].Name != "b" {
+		t.Fatalf("expected gaps to be zero filled, got %+v", data)
+	}
+
+	b := binder.NewBinder()
+	b.SparseArrayPolicy = binder.SparseArrayCompact
+	httpBinder := &binder.HttpBinder{Binder: b}
+	data = Request{}
+	if err := httpBinder.BindQueryParams(httptest.NewRequest(http.MethodGet, url, nil), &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(data.IDs) != 2 || data.IDs[0] != 1 || data.IDs[1] != 6 || len(data.Items) != 2 || data.Items[0].Name != "a" || data.Items[1].Name != "b" {
+		t.Fatalf("expected compacted slices, got %+v", data)
+	}
+
+	b.SparseArrayPolicy = binder.SparseArrayError
+	data = Request{}
+	err := httpBinder.BindQueryParams(httptest.NewRequest(http.MethodGet, url, nil), &data)
+	if !errors.Is(err, binder.ErrSparseArray) {
+		t.Fatalf("expected ErrSparseArray, got %v", err)
+	}
+	data = Request{}
+	if err := httpBinder.BindQueryParams(httptest.NewRequest(http.MethodGet, "/?ids[1]=2&ids[0]=1", nil), &data); err != nil || len(data.IDs) != 2 {
+		t.Fatalf("expected contiguous indices to be accepted, got %+v, %v", data, err)
+	}
+}
+
+func TestBindAppendNotation(t *testing.T) {
+	type Request struct {
+		Tags     []string `query:"tags" form:"tags"`
+		Elements []int    `query:"elements[]" form:"elements[]"`
+	}
+	b := binder.NewBinder()
+	b.StrictKeys = true
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	var data Request
+	req := httptest.NewRequest(http.MethodGet, "/?tags[]=a&tags[]=b&elements=1&elements[]=2", nil)
+	if err := httpBinder.BindQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(data.Tags, []string{"a", "b"}) || !reflect.DeepEqual(data.Elements, []int{1, 2}) {
+		t.Fatalf("expected append keys to be bound, got %+v", data)
+	}
+
+	data = Request{}
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("tags%5B%5D=c&elements%5B%5D=3"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if err := httpBinder.BindBody(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(data.Tags, []string{"c"}) || !reflect.DeepEqual(data.Elements, []int{3}) {
+		t.Fatalf("expected append keys to be bound from the form, got %+v", data)
+	}
+}
+
+func TestBindMethodOverride(t *testing.T) {
+	b := binder.NewBinder()
+	b.MethodOverrideField = binder.DefaultMethodOverrideField
+	b.StrictKeys = true
+	httpBinder := &binder.HttpBinder{Binder: b}
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/users/1", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req
+	}
+
+	req := newRequest("_method=put&name=John")
+	if method := httpBinder.EffectiveMethod(req); method != http.MethodPut {
+		t.Fatalf("expected method %s, got %s", http.MethodPut, method)
+	}
+
+	var data struct {
+		Method string `request:"method"`
+		Name   string `form:"name"`
+	}
+	if err := httpBinder.BindWith(newRequest("_method=PATCH&name=John"), &data, binder.WithBindRequestMetadata(true)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Method != http.MethodPatch || data.Name != "John" {
+		t.Fatalf("expected data to be bound with the effective method, got %+v", data)
+	}
+
+	values := map[string]string{}
+	if err := httpBinder.BindBody(newRequest("_method=DELETE&name=John"), &values); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, ok := values["_method"]; ok || values["name"] != "John" {
+		t.Fatalf("expected the override to be consumed, got %v", values)
+	}
+
+	if method := httpBinder.EffectiveMethod(newRequest("_method=CONNECT")); method != http.MethodPost {
+		t.Fatalf("expected unsupported overrides to be ignored, got %s", method)
+	}
+}
+
+func TestMethodOverrideIgnoresQuery(t *testing.T) {
+	b := binder.NewBinder()
+	b.MethodOverrideField = binder.DefaultMethodOverrideField
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	req := httptest.NewRequest(http.MethodPost, "/users/1?_method=DELETE", strings.NewReader("name=John"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if method := httpBinder.EffectiveMethod(req); method != http.MethodPost {
+		t.Fatalf("expected the query override to be ignored, got %s", method)
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("name", "John")
+	mw.Close()
+	req = httptest.NewRequest(http.MethodPost, "/users/1?_method=DELETE", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	if method := httpBinder.EffectiveMethod(req); method != http.MethodPost {
+		t.Fatalf("expected the query override of a multipart form to be ignored, got %s", method)
+	}
+}
+
+func TestBindCSRF(t *testing.T) {
+	b := binder.NewBinder()
+	b.CSRF = binder.NewCSRFTokenSource("csrf_token", "X-CSRF-Token", "csrf")
+	b.CSRF.Consume = true
+	b.StrictKeys = true
+	httpBinder := &binder.HttpBinder{Binder: b}
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.AddCookie(&http.Cookie{Name: "csrf", Value: "cookie-token"})
+		return req
+	}
+
+	var data struct {
+		Token  string `csrf:"token"`
+		Cookie string `csrf:"cookie"`
+		Name   string `form:"name"`
+	}
+	if err := httpBinder.Bind(newRequest("csrf_token=form-token&name=John"), &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Token != "form-token" || data.Cookie != "cookie-token" || data.Name != "John" {
+		t.Fatalf("expected the form token to be bound, got %+v", data)
+	}
+
+	req := newRequest("name=John")
+	req.Header.Set("X-CSRF-Token", "header-token")
+	data.Token = ""
+	if err := httpBinder.Bind(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Token != "header-token" {
+		t.Fatalf("expected the header token to be bound, got %+v", data)
+	}
+
+	values := map[string]string{}
+	if err := httpBinder.Bind(newRequest("csrf_token=form-token&name=John"), &values); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, ok := values["csrf_token"]; ok || values["name"] != "John" {
+		t.Fatalf("expected the token to be consumed, got %v", values)
+	}
+}
+
+func TestBindCSRFBodyTokenAndFirstCookie(t *testing.T) {
+	b := binder.NewBinder()
+	b.CSRF = binder.NewCSRFTokenSource("csrf_token", "X-CSRF-Token", "csrf")
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	var data struct {
+		Token  string `csrf:"token"`
+		Form   string `csrf:"form"`
+		Cookie string `csrf:"cookie"`
+	}
+	req := httptest.NewRequest(http.MethodPost, "/?csrf_token=query-token", strings.NewReader("name=John"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Add("Cookie", "csrf=first")
+	req.Header.Add("Cookie", "csrf=second")
+	if err := httpBinder.BindCSRF(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Token != "" || data.Form != "" || data.Cookie != "first" {
+		t.Fatalf("expected the query token to be ignored and the first cookie to be bound, got %+v", data)
+	}
+}
+
+func TestBindNestedFormParams(t *testing.T) {
+	type Extension struct {
+		Code string `form:"code"`
+	}
+	type Phone struct {
+		Number     string      `form:"number"`
+		Kind       string      `form:"kind"`
+		Extensions []Extension `form:"extensions"`
+	}
+	type Address struct {
+		City string   `form:"city"`
+		Tags []string `form:"tags"`
+	}
+	type User struct {
+		Name    string   `form:"name"`
+		Address Address  `form:"address"`
+		Billing *Address `form:"billing"`
+		Phones  []Phone  `form:"phones"`
+		Emails  []*Phone `form:"emails"`
+	}
+	type Request struct {
+		User User `form:"user"`
+	}
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req
+	}
+
+	form := url.Values{
+		"user[name]":                           {"John"},
+		"user[address][city]":                  {"Rome"},
+		"user[address][tags][]":                {"home", "main"},
+		"user[billing][city]":                  {"Milan"},
+		"user[phones][0][number]":              {"1"},
+		"user[phones][1][number]":              {"2"},
+		"user[phones][1][extensions][1][code]": {"b"},
+		"user[phones][1][extensions][0][code]": {"a"},
+		"user[emails][0][number]":              {"3"},
+	}
+	var data Request
+	if err := binder.BindHttpBody(newRequest(form.Encode()), &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	user := data.User
+	if user.Name != "John" || user.Address.City != "Rome" || !reflect.DeepEqual(user.Address.Tags, []string{"home", "main"}) {
+		t.Fatalf("expected nested structs to be bound, got %+v", user)
+	}
+	if user.Billing == nil || user.Billing.City != "Milan" {
+		t.Fatalf("expected nested pointer to be bound, got %+v", user.Billing)
+	}
+	if len(user.Phones) != 2 || user.Phones[1].Number != "2" || len(user.Phones[1].Extensions) != 2 || user.Phones[1].Extensions[1].Code != "b" {
+		t.Fatalf("expected nested slices to be bound, got %+v", user.Phones)
+	}
+	if len(user.Emails) != 1 || user.Emails[0].Number != "3" {
+		t.Fatalf("expected slices of pointers to be bound, got %+v", user.Emails)
+	}
+
+	// Rack-style append elements, the nth value of each key belongs to the nth element
+	data = Request{}
+	if err := binder.BindHttpBody(newRequest("user[phones][][number]=1&user[phones][][kind]=home&user[phones][][number]=2"), &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := []Phone{{Number: "1", Kind: "home"}, {Number: "2"}}
+	if !reflect.DeepEqual(data.User.Phones, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, data.User.Phones)
+	}
+}
+
+func TestBindAppendElementsGrouping(t *testing.T) {
+	type Item struct {
+		X string `form:"x"`
+		Y string `form:"y"`
+	}
+	var data struct {
+		Items []Item `form:"a"`
+	}
+	// Rack groups the keys in request order: [{x:1 y:2} {x:3}] and [{x:1} {x:2 y:3}]
+	for body, expected := range map[string][]Item{
+		"a[][x]=1&a[][y]=2&a[][x]=3": {{X: "1", Y: "2"}, {X: "3"}},
+		"a[][x]=1&a[][x]=2&a[][y]=3": {{X: "1", Y: "3"}, {X: "2"}},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		data.Items = nil
+		if err := binder.BindHttpBody(req, &data); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !reflect.DeepEqual(data.Items, expected) {
+			t.Fatalf("expected %+v for %s, got %+v", expected, body, data.Items)
+		}
+	}
+}
+
+func TestBindFormBodyOnly(t *testing.T) {
+	var data struct {
+		Name string `form:"name"`
+		Role string `form:"role"`
+	}
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/?role=admin", strings.NewReader("name=John"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req
+	}
+
+	if err := binder.BindHttpBody(newRequest(), &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Role != "admin" {
+		t.Fatalf("expected the query to be merged into the form by default, got %+v", data)
+	}
+
+	b := binder.NewBinder()
+	b.FormBodyOnly = true
+	httpBinder := &binder.HttpBinder{Binder: b}
+	data.Role = ""
+	if err := httpBinder.BindBody(newRequest(), &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Name != "John" || data.Role != "" {
+		t.Fatalf("expected the form to be bound from the body only, got %+v", data)
+	}
+}
+
+func TestBindTypedMapValues(t *testing.T) {
+	var data struct {
+		Counts  map[string]int      `query:"counts"`
+		Weights map[string]float64  `query:"weights"`
+		Flags   map[string]bool     `query:"flags"`
+		IDs     map[string][]uint   `query:"ids"`
+		Limits  *map[string]int64   `query:"limits"`
+		Names   map[string][]string `query:"names"`
+	}
+	req := httptest.NewRequest(http.MethodGet, "/?counts[a]=1&counts[b]=2&weights[x]=0.5&flags[on]=true&ids[a]=1&ids[a]=2&limits[max]=10&names[a]=x", nil)
+	if err := binder.BindHttpQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(data.Counts, map[string]int{"a": 1, "b": 2}) || data.Weights["x"] != 0.5 || !data.Flags["on"] {
+		t.Fatalf("expected typed maps to be bound, got %+v", data)
+	}
+	if !reflect.DeepEqual(data.IDs, map[string][]uint{"a": {1, 2}}) || data.Limits == nil || (*data.Limits)["max"] != 10 || data.Names["a"][0] != "x" {
+		t.Fatalf("expected typed maps to be bound, got %+v", data)
+	}
+
+	counts := map[string]int{}
+	req = httptest.NewRequest(http.MethodGet, "/?a=1&b=x", nil)
+	if err := binder.BindHttpQueryParams(req, &counts); err == nil {
+		t.Fatalf("expected an error for an invalid value, got %v", counts)
+	}
+}
+
+func TestBindMultipleFilesOrder(t *testing.T) {
+	newRequest := func(fields ...string) *http.Request {
+		var buf bytes.Buffer
+		writer := multipart.NewWriter(&buf)
+		for i, field := range fields {
+			part, _ := writer.CreateFormFile(field, fmt.Sprintf("file%d.txt", i))
+			part.Write([]byte("content"))
+		}
+		writer.Close()
+		req := httptest.NewRequest(http.MethodPost, "/", &buf)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		return req
+	}
+
+	var data struct {
+		Documents []*multipart.FileHeader `form:"documents"`
+		Uploads   []binder.UploadedFile   `form:"uploads"`
+		Avatar    *binder.UploadedFile    `form:"avatar"`
+	}
+	req := newRequest("documents", "uploads[]", "documents", "uploads[]", "avatar", "documents", "uploads[]")
+	if err := binder.BindHttpBody(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(data.Documents) != 3 || data.Documents[0].Filename != "file0.txt" || data.Documents[1].Filename != "file2.txt" || data.Documents[2].Filename != "file5.txt" {
+		t.Fatalf("expected the files in upload order, got %+v", data.Documents)
+	}
+	if len(data.Uploads) != 3 {
+		t.Fatalf("expected 3 uploads, got %+v", data.Uploads)
+	}
+	for i, expected := range []string{"file1.txt", "file3.txt", "file6.txt"} {
+		if upload := data.Uploads[i]; upload.Filename != expected || upload.Index != i || upload.Field != "uploads" {
+			t.Fatalf("expected upload %d to be %s, got %+v", i, expected, upload)
+		}
+	}
+	if data.Avatar == nil || data.Avatar.Filename != "file4.txt" || data.Avatar.Index != 0 {
+		t.Fatalf("expected the avatar to be bound, got %+v", data.Avatar)
+	}
+
+	// indexed keys are ordered by index
+	data.Documents = nil
+	if err := binder.BindHttpBody(newRequest("documents[1]", "documents[0]"), &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(data.Documents) != 2 || data.Documents[0].Filename != "file1.txt" || data.Documents[1].Filename != "file0.txt" {
+		t.Fatalf("expected the files in index order, got %+v", data.Documents)
+	}
+}
+
+func TestBindNonStringMapKeys(t *testing.T) {
+	var data struct {
+		Names  map[int]string        `query:"names"`
+		Flags  map[uint8]bool        `query:"flags"`
+		Hosts  map[netip.Addr]string `query:"hosts"`
+		Scores map[int64][]float64   `query:"scores"`
+	}
+	req := httptest.NewRequest(http.MethodGet, "/?names[1]=a&names[2]=b&flags[7]=true&hosts[10.0.0.1]=gateway&scores[3]=1.5&scores[3]=2", nil)
+	if err := binder.BindHttpQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(data.Names, map[int]string{1: "a", 2: "b"}) || !data.Flags[7] || !reflect.DeepEqual(data.Scores[3], []float64{1.5, 2}) {
+		t.Fatalf("expected maps to be bound with converted keys, got %+v", data)
+	}
+	if data.Hosts[netip.MustParseAddr("10.0.0.1")] != "gateway" {
+		t.Fatalf("expected keys to use the type converters, got %+v", data.Hosts)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?names[abc]=a", nil)
+	err := binder.BindHttpQueryParams(req, &data)
+	var keyErr *binder.MapKeyError
+	if !errors.As(err, &keyErr) || keyErr.Key != "abc" || keyErr.Type != reflect.TypeOf(0) {
+		t.Fatalf("expected a MapKeyError, got %v", err)
+	}
+}
+
+func TestBindMultipartIntoMap(t *testing.T) {
+	newRequest := func() *http.Request {
+		var buf bytes.Buffer
+		writer := multipart.NewWriter(&buf)
+		writer.WriteField("name", "John")
+		for i, field := range []string{"avatar", "documents", "documents"} {
+			part, _ := writer.CreateFormFile(field, fmt.Sprintf("file%d.txt", i))
+			part.Write([]byte("content"))
+		}
+		writer.Close()
+		req := httptest.NewRequest(http.MethodPost, "/", &buf)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		return req
+	}
+
+	data := map[string]interface{}{}
+	if err := binder.BindHttpBody(newRequest(), &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data["name"] != "John" {
+		t.Fatalf("expected values to be bound, got %v", data)
+	}
+	if avatar, ok := data["avatar"].(*multipart.FileHeader); !ok || avatar.Filename != "file0.txt" {
+		t.Fatalf("expected the file to be bound, got %v", data["avatar"])
+	}
+	if documents, ok := data["documents"].([]*multipart.FileHeader); !ok || len(documents) != 2 || documents[1].Filename != "file2.txt" {
+		t.Fatalf("expected the files to be bound, got %v", data["documents"])
+	}
+
+	files := map[string][]*multipart.FileHeader{}
+	if err := binder.BindHttpBody(newRequest(), &files); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(files) != 2 || len(files["avatar"]) != 1 || len(files["documents"]) != 2 {
+		t.Fatalf("expected only the files to be bound, got %v", files)
+	}
+}
+
+func TestBindXMLParamOverlay(t *testing.T) {
+	type Meta struct {
+		Tag string `xml:"tag"`
+	}
+	type User struct {
+		XMLName xml.Name `xml:"user"`
+		ID      int      `xml:"id,attr"`
+		Name    string   `xml:"name"`
+		City    string   `xml:"address>city"`
+		Meta    Meta     `xml:"meta"`
+	}
+	newRequest := func() *http.Request {
+		body := `<user id="1"><name>John</name><address><city>Rome</city></address><meta><tag>a</tag></meta></user>`
+		req := httptest.NewRequest(http.MethodPost, "/?id=2&address.city=Milan&meta.tag=b", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/xml")
+		return req
+	}
+
+	var data User
+	if err := binder.BindHttpBody(newRequest(), &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.ID != 1 || data.City != "Rome" || data.Meta.Tag != "a" {
+		t.Fatalf("expected no overlay by default, got %+v", data)
+	}
+
+	b := binder.NewBinder()
+	b.XMLParamOverlay = true
+	httpBinder := &binder.HttpBinder{Binder: b}
+	data = User{}
+	if err := httpBinder.BindBody(newRequest(), &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.ID != 2 || data.Name != "John" || data.City != "Milan" || data.Meta.Tag != "b" {
+		t.Fatalf("expected the query params to overlay the XML body, got %+v", data)
+	}
+}
+
+func TestBindMapMultiValues(t *testing.T) {
+	newRequest := func() *http.Request {
+		return httptest.NewRequest(http.MethodGet, "/?tag=a&tag=b&name=John", nil)
+	}
+
+	data := map[string]interface{}{}
+	if err := binder.BindHttpQueryParams(newRequest(), &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data["tag"] != "a" {
+		t.Fatalf("expected the first value by default, got %v", data)
+	}
+
+	b := binder.NewBinder()
+	b.MapMultiValues = true
+	httpBinder := &binder.HttpBinder{Binder: b}
+	data = map[string]interface{}{}
+	if err := httpBinder.BindQueryParams(newRequest(), &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(data["tag"], []string{"a", "b"}) || data["name"] != "John" {
+		t.Fatalf("expected repeated keys to be bound as []string, got %v", data)
+	}
+}
+
+func TestBindFieldNameFallback(t *testing.T) {
+	type Request struct {
+		UserID    int
+		FirstName string
+		Tags      []string
+		Email     string `json:"email"`
+		Limits    map[string]int
+	}
+	newRequest := func() *http.Request {
+		return httptest.NewRequest(http.MethodGet, "/?user_id=1&firstName=John&tags=a&tags=b&email=x&limits[max]=5", nil)
+	}
+
+	var data Request
+	if err := binder.BindHttpQueryParams(newRequest(), &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.UserID != 0 || data.FirstName != "" {
+		t.Fatalf("expected untagged fields to be ignored by default, got %+v", data)
+	}
+
+	b := binder.NewBinder()
+	b.UseFieldNameFallback = true
+	b.StrictKeys = true
+	httpBinder := &binder.HttpBinder{Binder: b}
+	err := httpBinder.BindQueryParams(newRequest(), &data)
+	var unknown *binder.UnknownFieldError
+	if !errors.As(err, &unknown) || !reflect.DeepEqual(unknown.Fields, []string{"email"}) {
+		t.Fatalf("expected only the tagged field key to be unknown, got %v", err)
+	}
+
+	b.StrictKeys = false
+	data = Request{}
+	if err := httpBinder.BindQueryParams(newRequest(), &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.UserID != 1 || data.FirstName != "John" || !reflect.DeepEqual(data.Tags, []string{"a", "b"}) || data.Limits["max"] != 5 {
+		t.Fatalf("expected untagged fields to be bound by name, got %+v", data)
+	}
+	if data.Email != "" {
+		t.Fatalf("expected tagged fields to keep their tags, got %+v", data)
+	}
+}
+
+type testXMLCode string
+
+func (c *testXMLCode) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var value string
+	if err := d.DecodeElement(&value, &start); err != nil {
+		return err
+	}
+	*c = testXMLCode(strings.ToUpper(value))
+	return nil
+}
+
+func TestBindXMLParamOverlayDecoderFields(t *testing.T) {
+	type Extra struct {
+		XMLName xml.Name
+		Value   string `xml:"value"`
+	}
+	type Note struct {
+		Lang string `xml:"lang,attr"`
+		Text string `xml:",chardata"`
+	}
+	type Document struct {
+		XMLName xml.Name    `xml:"document"`
+		ID      string      `xml:"id,attr"`
+		Note    Note        `xml:"note"`
+		Code    testXMLCode `xml:"code"`
+		Raw     string      `xml:",innerxml"`
+		Extra   Extra       `xml:",any"`
+	}
+	body := `<document id="1"><note lang="en">hello</note><code>abc</code><extra><value>x</value></extra></document>`
+	req := httptest.NewRequest(http.MethodPost, "/?id=2&note.lang=it&code=zzz&value=y&Value=y", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/xml")
+
+	b := binder.NewBinder()
+	b.XMLParamOverlay = true
+	httpBinder := &binder.HttpBinder{Binder: b}
+	var data Document
+	if err := httpBinder.BindBody(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.ID != "2" || data.Note.Lang != "it" || data.Note.Text != "hello" {
+		t.Fatalf("expected attributes to be overlaid and chardata kept, got %+v", data)
+	}
+	if data.Code != "ABC" || data.Extra.XMLName.Local != "extra" || data.Extra.Value != "x" || data.Raw != body[len(`<document id="1">`):len(body)-len(`</document>`)] {
+		t.Fatalf("expected the fields owned by the XML decoder to be kept, got %+v", data)
+	}
+}
+
+func TestBindNameMapper(t *testing.T) {
+	for name, expected := range map[string][3]string{
+		"UserID":       {"user_id", "user-id", "userId"},
+		"HTTPServerID": {"http_server_id", "http-server-id", "httpServerId"},
+		"FirstName":    {"first_name", "first-name", "firstName"},
+		"Address2":     {"address2", "address2", "address2"},
+	} {
+		got := [3]string{binder.SnakeCaseName(name), binder.KebabCaseName(name), binder.CamelCaseName(name)}
+		if got != expected {
+			t.Fatalf("expected %s to be mapped to %v, got %v", name, expected, got)
+		}
+	}
+
+	type Request struct {
+		UserID    int
+		FirstName string
+		Tags      []string
+		Email     string `query:"mail"`
+	}
+	b := binder.NewBinder()
+	b.NameMapper = binder.KebabCaseName
+	httpBinder := &binder.HttpBinder{Binder: b}
+	req := httptest.NewRequest(http.MethodGet, "/?user-id=1&first-name=John&first_name=Jane&tags=a&mail=x", nil)
+	var data Request
+	if err := httpBinder.BindQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.UserID != 1 || data.FirstName != "John" || !reflect.DeepEqual(data.Tags, []string{"a"}) || data.Email != "x" {
+		t.Fatalf("expected untagged fields to be bound by their mapped name, got %+v", data)
+	}
+}
+
+func TestBindRequireBody(t *testing.T) {
+	b := binder.NewBinder()
+	required := &binder.HttpBinder{Binder: b.RequireBody(true)}
+	optional := &binder.HttpBinder{Binder: b}
+
+	var data TestStruct
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Content-Type", "application/json")
+	if err := optional.Bind(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := required.Bind(req, &data); !errors.Is(err, binder.ErrEmptyBody) {
+		t.Fatalf("expected ErrEmptyBody, got %v", err)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"John"}`))
+	req.Header.Set("Content-Type", "application/json")
+	if err := required.Bind(req, &data); err != nil || data.Name != "John" {
+		t.Fatalf("expected the body to be bound, got %+v, %v", data, err)
+	}
+}
+
+func TestBindRequireChunkedBody(t *testing.T) {
+	required := &binder.HttpBinder{Binder: binder.NewBinder().RequireBody(true)}
+	newRequest := func(contentType string, body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		req.ContentLength = -1
+		return req
+	}
+
+	var data TestStruct
+	if err := required.Bind(newRequest("application/json", ""), &data); !errors.Is(err, binder.ErrEmptyBody) {
+		t.Fatalf("expected ErrEmptyBody, got %v", err)
+	}
+	if err := required.Bind(newRequest("application/json", `{"name":"John"}`), &data); err != nil || data.Name != "John" {
+		t.Fatalf("expected the chunked body to be bound, got %+v, %v", data, err)
+	}
+	if err := required.Bind(newRequest("application/x-www-form-urlencoded", "name=Jane&age=30"), &data); err != nil || data.Name != "Jane" || data.Age != 30 {
+		t.Fatalf("expected the chunked form to be bound, got %+v, %v", data, err)
+	}
+}
+
+func TestBindJSONTagFallback(t *testing.T) {
+	type Request struct {
+		Name   string   `json:"name,omitempty"`
+		Age    int      `json:"age" query:"years" form:"years"`
+		Tags   []string `json:"tags"`
+		Secret string   `json:"-"`
+	}
+	newRequest := func() *http.Request {
+		return httptest.NewRequest(http.MethodGet, "/?name=John&age=1&years=30&tags=a&tags=b&-=x", nil)
+	}
+
+	var data Request
+	if err := binder.BindHttpQueryParams(newRequest(), &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Name != "" || data.Age != 30 {
+		t.Fatalf("expected json tags to be ignored by default, got %+v", data)
+	}
+
+	b := binder.NewBinder()
+	b.UseJSONTagFallback = true
+	httpBinder := &binder.HttpBinder{Binder: b}
+	data = Request{}
+	if err := httpBinder.BindQueryParams(newRequest(), &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Name != "John" || data.Age != 30 || !reflect.DeepEqual(data.Tags, []string{"a", "b"}) || data.Secret != "" {
+		t.Fatalf("expected the json tag names to be used, got %+v", data)
+	}
+
+	data = Request{}
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("name=Jane&years=20"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if err := httpBinder.BindBody(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Name != "Jane" || data.Age != 20 {
+		t.Fatalf("expected the json tag names to be used for forms, got %+v", data)
+	}
+}
+
+func TestBindBodyLengthMismatch(t *testing.T) {
+	newRequest := func(contentType string, body string, contentLength int64) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		req.ContentLength = contentLength
+		return req
+	}
+
+	var data TestStruct
+	var lengthErr *binder.BodyLengthError
+	err := binder.BindHttpBody(newRequest("application/json", `{"name":"Jo`, 100), &data)
+	if !errors.As(err, &lengthErr) || !lengthErr.Truncated || lengthErr.ContentLength != 100 || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected a truncated body error, got %v", err)
+	}
+
+	err = binder.BindHttpBody(newRequest("application/json", `{"name":"John"}`, 5), &data)
+	if !errors.As(err, &lengthErr) || lengthErr.Truncated {
+		t.Fatalf("expected an exceeded length error, got %v", err)
+	}
+
+	// the body of server requests fails with io.ErrUnexpectedEOF when truncated
+	req := newRequest("application/x-www-form-urlencoded", "", 100)
+	req.Body = io.NopCloser(io.MultiReader(strings.NewReader("name=John"), iotest.ErrReader(io.ErrUnexpectedEOF)))
+	err = binder.BindHttpBody(req, &data)
+	if !errors.As(err, &lengthErr) || !lengthErr.Truncated {
+		t.Fatalf("expected a truncated form error, got %v", err)
+	}
+
+	if err := binder.BindHttpBody(newRequest("application/json", `{"name":"John"}`, 15), &data); err != nil || data.Name != "John" {
+		t.Fatalf("expected the body to be bound, got %+v, %v", data, err)
+	}
+}
+
+func TestBindExcludedFields(t *testing.T) {
+	type Profile struct {
+		Bio     string `json:"bio" form:"bio"`
+		IsAdmin bool   `json:"is_admin" form:"is_admin" bind:"-"`
+	}
+	type Request struct {
+		Name     string    `query:"name" json:"name"`
+		Internal string    `query:"-" json:"internal"`
+		IsAdmin  bool      `query:"is_admin" json:"is_admin" form:"is_admin" bind:"-"`
+		Profile  *Profile  `json:"profile" form:"profile"`
+		Profiles []Profile `json:"profiles"`
+	}
+
+	var data Request
+	req := httptest.NewRequest(http.MethodGet, "/?name=John&internal=x&-=x&is_admin=true", nil)
+	if err := binder.BindHttpQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Name != "John" || data.Internal != "" || data.IsAdmin {
+		t.Fatalf("expected excluded fields not to be bound from the query, got %+v", data)
+	}
+
+	data = Request{IsAdmin: true}
+	body := `{"name":"John","is_admin":false,"profile":{"bio":"x","is_admin":true},"profiles":[{"is_admin":true}]}`
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if err := binder.BindHttpBody(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !data.IsAdmin || data.Profile == nil || data.Profile.Bio != "x" || data.Profile.IsAdmin || data.Profiles[0].IsAdmin {
+		t.Fatalf("expected excluded fields not to be bound from the body, got %+v %+v", data, data.Profile)
+	}
+
+	data = Request{}
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("is_admin=true&profile.bio=y&profile.is_admin=true"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if err := binder.BindHttpBody(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.IsAdmin || data.Profile == nil || data.Profile.Bio != "y" || data.Profile.IsAdmin {
+		t.Fatalf("expected excluded fields not to be bound from the form, got %+v %+v", data, data.Profile)
+	}
+}
+
+func TestBindExcludedFieldsInMapValues(t *testing.T) {
+	type Prof struct {
+		Bio     string `json:"bio"`
+		IsAdmin bool   `bind:"-"`
+	}
+	data := map[string]Prof{"kept": {Bio: "a", IsAdmin: true}}
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"x":{"bio":"b","IsAdmin":true}}`))
+	req.Header.Set("Content-Type", "application/json")
+	if err := binder.BindHttpBody(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data["x"] != (Prof{Bio: "b"}) || data["kept"] != (Prof{Bio: "a", IsAdmin: true}) {
+		t.Fatalf("expected excluded fields not to be bound into map values, got %+v", data)
+	}
+
+	var nested struct {
+		Profiles map[string]*Prof `json:"p"`
+		Any      interface{}      `json:"any"`
+	}
+	nested.Any = &Prof{}
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"p":{"x":{"IsAdmin":true}},"any":{"IsAdmin":true}}`))
+	req.Header.Set("Content-Type", "application/json")
+	if err := binder.BindHttpBody(req, &nested); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if nested.Profiles["x"] == nil || nested.Profiles["x"].IsAdmin || nested.Any.(*Prof).IsAdmin {
+		t.Fatalf("expected excluded fields not to be bound through maps and interfaces, got %+v", nested)
+	}
+}
+
+func TestBindScratchPool(t *testing.T) {
+	form := deepForm()
+	bind := func(useScratchPool bool) benchDeep {
+		b := binder.NewBinder()
+		b.UseScratchPool = useScratchPool
+		httpBinder := &binder.HttpBinder{Binder: b}
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		var data benchDeep
+		if err := httpBinder.BindBody(req, &data); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		return data
+	}
+
+	expected := bind(false)
+	if len(expected.Items) != 20 || expected.User.Address.City != "Rome" {
+		t.Fatalf("expected the deep form to be bound, got %+v", expected)
+	}
+	// repeated binds reuse the pooled maps
+	for i := 0; i < 3; i++ {
+		if got := bind(true); !reflect.DeepEqual(got, expected) {
+			t.Fatalf("expected %+v, got %+v", expected, got)
+		}
+	}
+}
+
+func TestBindReadOnly(t *testing.T) {
+	type Account struct {
+		ID    int    `query:"id,readonly" json:"id,readonly"`
+		Owner string `query:"owner" json:"owner" bind:",readonly"`
+		Name  string `query:"name" json:"name"`
+	}
+	b := binder.NewBinder()
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	req := httptest.NewRequest(http.MethodGet, "/?id=7&owner=mallory&name=John", nil)
+	data := Account{ID: 1, Owner: "alice"}
+	if err := httpBinder.BindQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.ID != 1 || data.Owner != "alice" || data.Name != "John" {
+		t.Fatalf("expected the read-only fields to be kept, got %+v", data)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"id":7,"owner":"mallory","name":"John"}`))
+	req.Header.Set("Content-Type", "application/json")
+	data = Account{ID: 1, Owner: "alice"}
+	if err := httpBinder.BindBody(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.ID != 1 || data.Owner != "alice" || data.Name != "John" {
+		t.Fatalf("expected the read-only fields to be kept, got %+v", data)
+	}
+
+	b.ReadOnlyPolicy = binder.ReadOnlyError
+	var roErr *binder.ReadOnlyFieldError
+	req = httptest.NewRequest(http.MethodGet, "/?name=John&ID=7", nil)
+	if err := httpBinder.BindQueryParams(req, &Account{}); !errors.As(err, &roErr) || roErr.Source != "query" || roErr.Field != "id" {
+		t.Fatalf("expected a read-only error for id, got %v", err)
+	}
+	req = httptest.NewRequest(http.MethodGet, "/?name=John", nil)
+	if err := httpBinder.BindQueryParams(req, &Account{}); err != nil {
+		t.Fatalf("expected no error without read-only values, got %v", err)
+	}
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"owner":"mallory"}`))
+	req.Header.Set("Content-Type", "application/json")
+	data = Account{Owner: "alice"}
+	if err := httpBinder.BindBody(req, &data); !errors.As(err, &roErr) || roErr.Field != "owner" {
+		t.Fatalf("expected a read-only error for owner, got %v", err)
+	}
+	if data.Owner != "alice" {
+		t.Fatalf("expected the read-only field to be restored, got %q", data.Owner)
+	}
+}
+
+func TestReadOnlyFieldErrorKey(t *testing.T) {
+	type Account struct {
+		Owner string `query:"owner_id" form:"owner_id" json:"owner_id" xml:"owner" bind:",readonly"`
+	}
+	b := binder.NewBinder()
+	b.ReadOnlyPolicy = binder.ReadOnlyError
+	httpBinder := &binder.HttpBinder{Binder: b}
+	newRequest := func(contentType string, body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		return req
+	}
+
+	for _, req := range []*http.Request{
+		newRequest("application/x-www-form-urlencoded", "owner_id=mallory"),
+		newRequest("application/json", `{"owner_id":"mallory"}`),
+	} {
+		var roErr *binder.ReadOnlyFieldError
+		if err := httpBinder.BindBody(req, &Account{}); !errors.As(err, &roErr) || roErr.Field != "owner_id" {
+			t.Fatalf("expected a read-only error for the owner_id key, got %v", err)
+		}
+	}
+	var roErr *binder.ReadOnlyFieldError
+	req := newRequest("application/xml", `<Account><owner>mallory</owner></Account>`)
+	if err := httpBinder.BindBody(req, &Account{}); !errors.As(err, &roErr) || roErr.Field != "owner" {
+		t.Fatalf("expected a read-only error for the owner element, got %v", err)
+	}
+}
+
+type strictFieldsBase struct {
+	Tenant string `query:"tenant"`
+}
+
+func TestBindStrictFields(t *testing.T) {
+	type Unexported struct {
+		Name string `query:"name"`
+		id   int    `query:"id"`
+	}
+	type Embedded struct {
+		strictFieldsBase
+		Name string `query:"name"`
+	}
+	type Ignored struct {
+		Name  string `query:"name"`
+		count int    `query:"-"`
+		cache map[string]string
+	}
+	b := binder.NewBinder()
+	httpBinder := &binder.HttpBinder{Binder: b}
+	req := httptest.NewRequest(http.MethodGet, "/?name=John&id=1&tenant=acme", nil)
+
+	var unexported Unexported
+	if err := httpBinder.BindQueryParams(req, &unexported); err != nil || unexported.Name != "John" || unexported.id != 0 {
+		t.Fatalf("expected unexported fields to be skipped, got %+v, %v", unexported, err)
+	}
+
+	b.StrictFields = true
+	var fieldErr *binder.UnsettableFieldError
+	if err := httpBinder.BindQueryParams(req, &Unexported{}); !errors.As(err, &fieldErr) || fieldErr.Field != "id" || fieldErr.Reason != "unexported" {
+		t.Fatalf("expected an unsettable field error for id, got %v", err)
+	}
+	if err := httpBinder.BindQueryParams(req, &Embedded{}); !errors.As(err, &fieldErr) || fieldErr.Field != "strictFieldsBase" {
+		t.Fatalf("expected an unsettable field error for the embedded struct, got %v", err)
+	}
+	var ignored Ignored
+	if err := httpBinder.BindQueryParams(req, &ignored); err != nil || ignored.Name != "John" {
+		t.Fatalf("expected untagged and excluded unexported fields to be skipped, got %+v, %v", ignored, err)
+	}
+}
+
+func TestBindFieldSources(t *testing.T) {
+	type Account struct {
+		ID   int    `json:"id" bind:"id,sources=path>query>json"`
+		Name string `query:"name" json:"name" bind:",sources=json"`
+	}
+	newRequest := func(path string, target string, body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if path != "" {
+			req.Pattern = "/accounts/{id}"
+			req.SetPathValue("id", path)
+		}
+		return req
+	}
+
+	for _, tc := range []struct {
+		path, target, body string
+		expected           Account
+	}{
+		{"9", "/accounts/9?id=8&name=query", `{"id":7,"name":"json"}`, Account{ID: 9, Name: "json"}},
+		{"", "/accounts?id=8&name=query", `{"id":7}`, Account{ID: 8}},
+		{"", "/accounts", `{"id":7,"name":"json"}`, Account{ID: 7, Name: "json"}},
+	} {
+		var data Account
+		if err := binder.BindHttp(newRequest(tc.path, tc.target, tc.body), &data); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if data != tc.expected {
+			t.Fatalf("expected %+v, got %+v", tc.expected, data)
+		}
+	}
+
+	// single source binds only filter the sources
+	var data Account
+	if err := binder.BindHttpQueryParams(newRequest("", "/accounts?id=8&name=query", ""), &data); err != nil || data.ID != 8 || data.Name != "" {
+		t.Fatalf("expected only the id to be bound from the query, got %+v, %v", data, err)
+	}
+}
+
+func TestBindSelected(t *testing.T) {
+	type User struct {
+		ID      int    `query:"id" json:"id"`
+		Name    string `query:"name" json:"name"`
+		Session string `cookie:"session"`
+	}
+	b := binder.NewBinder()
+	b.RegisterBindSource("cookie", func(r binder.BindableRequest, i interface{}) error {
+		if cookie, err := http.ParseCookie(r.GetHeaders().Get("Cookie")); err == nil && len(cookie) > 0 {
+			i.(*User).Session = cookie[0].Value
+		}
+		return nil
+	})
+	httpBinder := &binder.HttpBinder{Binder: b}
+	before := len(b.BindOrder)
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/?id=1&name=query", strings.NewReader(`{"name":"body"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Cookie", "session=abc")
+		return req
+	}
+
+	var data User
+	if err := httpBinder.BindSelected(newRequest(), &data, binder.SourceQuery); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data != (User{ID: 1, Name: "query"}) {
+		t.Fatalf("expected only the query to be bound, got %+v", data)
+	}
+
+	data = User{}
+	if err := httpBinder.BindSelected(newRequest(), &data, binder.SourceQuery, binder.SourceBody, "cookie"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data != (User{ID: 1, Name: "body", Session: "abc"}) {
+		t.Fatalf("expected the body to override the query, got %+v", data)
+	}
+
+	if err := httpBinder.BindSelected(newRequest(), &data, "unknown"); !errors.Is(err, binder.ErrUnknownSource) {
+		t.Fatalf("expected ErrUnknownSource, got %v", err)
+	}
+	if len(b.BindOrder) != before {
+		t.Fatalf("expected BindOrder to be left untouched, got %d steps, want %d", len(b.BindOrder), before)
+	}
+}
+
+type ShadowAudit struct {
+	ID      int    `query:"id"`
+	Created string `query:"created"`
+	Note    string `query:"note"`
+}
+
+type ShadowMeta struct {
+	Note string `query:"note"`
+}
+
+func TestBindShadowedEmbeddedFields(t *testing.T) {
+	type Resource struct {
+		ShadowAudit
+		*ShadowMeta
+		ID int `query:"id"`
+	}
+	req := httptest.NewRequest(http.MethodGet, "/?id=1&created=today&note=hi", nil)
+
+	data := Resource{ShadowMeta: &ShadowMeta{}}
+	if err := binder.BindHttpQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// the outer field shadows the embedded one, ambiguous fields at the same depth are not bound
+	if data.ID != 1 || data.ShadowAudit.ID != 0 || data.Created != "today" || data.ShadowAudit.Note != "" || data.ShadowMeta.Note != "" {
+		t.Fatalf("expected encoding/json depth rules, got %+v", data)
+	}
+
+	b := binder.NewBinder()
+	b.StrictAmbiguousFields = true
+	httpBinder := &binder.HttpBinder{Binder: b}
+	var ambiguousErr *binder.AmbiguousFieldError
+	if err := httpBinder.BindQueryParams(req, &data); !errors.As(err, &ambiguousErr) || ambiguousErr.Field != "note" {
+		t.Fatalf("expected an ambiguous field error for note, got %v", err)
+	}
+}
+
+func TestBindUnknownJSONFields(t *testing.T) {
+	type Base struct {
+		ID int `json:"id"`
+	}
+	type Event struct {
+		Base
+		Name  string                     `json:"name"`
+		Extra map[string]json.RawMessage `json:"-" bind:",unknown"`
+	}
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	var data Event
+	if err := binder.BindHttpBody(newRequest(`{"id":1,"NAME":"signup","source":"web","tags":["a"],"extra":1}`), &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.ID != 1 || data.Name != "signup" || len(data.Extra) != 3 || string(data.Extra["source"]) != `"web"` || string(data.Extra["tags"]) != `["a"]` || string(data.Extra["extra"]) != `1` {
+		t.Fatalf("expected the unknown keys to be collected, got %+v", data)
+	}
+
+	data = Event{}
+	if err := binder.BindHttpBody(newRequest(`{"id":1}`), &data); err != nil || data.Extra != nil {
+		t.Fatalf("expected no unknown keys, got %+v, %v", data.Extra, err)
+	}
+
+	type Typed struct {
+		Name  string            `json:"name"`
+		Attrs map[string]string `json:"-" bind:",unknown"`
+	}
+	var typed Typed
+	if err := binder.BindHttpBody(newRequest(`{"name":"x","color":"red"}`), &typed); err != nil || typed.Attrs["color"] != "red" {
+		t.Fatalf("expected the unknown keys to be decoded, got %+v, %v", typed, err)
+	}
+	var valueErr *binder.MapValueError
+	if err := binder.BindHttpBody(newRequest(`{"name":"x","size":1}`), &typed); !errors.As(err, &valueErr) || valueErr.Key != "size" {
+		t.Fatalf("expected a map value error for size, got %v", err)
+	}
+}
+
+func TestBinderWith(t *testing.T) {
+	type Search struct {
+		Query string `query:"q"`
+	}
+	base := binder.NewBinder()
+	strict := base.WithStrictKeys(true).WithTypeConverter(reflect.TypeOf(""), func(values []string, dst reflect.Value, _ reflect.StructField) error {
+		dst.SetString(strings.ToUpper(values[0]))
+		return nil
+	})
+	if base.StrictKeys || len(base.TypeConverters) == len(strict.TypeConverters) {
+		t.Fatalf("expected the base binder to be left untouched")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/?q=go&page=2", nil)
+	var data Search
+	if err := (&binder.HttpBinder{Binder: base}).Bind(req, &data); err != nil || data.Query != "go" {
+		t.Fatalf("expected the base binder to bind, got %+v, %v", data, err)
+	}
+	var unknownErr *binder.UnknownFieldError
+	if err := (&binder.HttpBinder{Binder: strict}).Bind(req, &data); !errors.As(err, &unknownErr) {
+		t.Fatalf("expected the derived binder to reject unknown keys in Bind, got %v", err)
+	}
+
+	upper := strict.WithStrictKeys(false).WithBindOrder(binder.SourceQuery)
+	data = Search{}
+	if err := (&binder.HttpBinder{Binder: upper}).Bind(req, &data); err != nil || data.Query != "GO" {
+		t.Fatalf("expected the derived bind order to use the derived settings, got %+v, %v", data, err)
+	}
+
+	if large := base.WithMaxBodySize(1 << 30); large.MaxBodySize != 1<<30 || base.MaxBodySize != binder.DefaultBodySize {
+		t.Fatalf("expected only the derived binder to change, got %d and %d", large.MaxBodySize, base.MaxBodySize)
+	}
+}
+
+func TestBindCompatLevel(t *testing.T) {
+	type Page struct {
+		Page  int    `query:"page"`
+		Sort  string `query:"sort"`
+		Debug bool   `query:"debug"`
+	}
+	req := httptest.NewRequest(http.MethodGet, "/?page=&SORT=name&debug=&tag=a&tag=b", nil)
+
+	v1 := binder.NewBinder()
+	v1.CompatLevel = binder.CompatV1
+	data := Page{Page: 1, Debug: true}
+	if err := (&binder.HttpBinder{Binder: v1}).BindQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data != (Page{Page: 0, Sort: "name", Debug: false}) {
+		t.Fatalf("expected the v1 behaviors, got %+v", data)
+	}
+	values := map[string]interface{}{}
+	if err := (&binder.HttpBinder{Binder: v1}).BindQueryParams(req, &values); err != nil || values["tag"] != "a" {
+		t.Fatalf("expected the first value with v1, got %v, %v", values["tag"], err)
+	}
+
+	v2 := v1.With(func(c *binder.DefaultBinder) { c.CompatLevel = binder.CompatV2 })
+	data = Page{Page: 1, Debug: true}
+	if err := (&binder.HttpBinder{Binder: v2}).BindQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data != (Page{Page: 1, Sort: "", Debug: true}) {
+		t.Fatalf("expected the v2 behaviors, got %+v", data)
+	}
+	values = map[string]interface{}{}
+	if err := (&binder.HttpBinder{Binder: v2}).BindQueryParams(req, &values); err != nil || !reflect.DeepEqual(values["tag"], []string{"a", "b"}) {
+		t.Fatalf("expected all the values with v2, got %v, %v", values["tag"], err)
+	}
+}
+
+func TestSetValueFromStrings(t *testing.T) {
+	var count int
+	if err := binder.SetValueFromStrings(reflect.ValueOf(&count).Elem(), []string{"42"}, binder.ConvertOptions{}); err != nil || count != 42 {
+		t.Fatalf("expected 42, got %d, %v", count, err)
+	}
+
+	var ids []int64
+	if err := binder.SetValueFromStrings(reflect.ValueOf(&ids).Elem(), []string{"1", "2"}, binder.ConvertOptions{Key: "ids"}); err != nil || !reflect.DeepEqual(ids, []int64{1, 2}) {
+		t.Fatalf("expected [1 2], got %v, %v", ids, err)
+	}
+
+	type Filter struct {
+		Since time.Time `query:"since,layout=2006-01-02"`
+		Tags  []string  `query:"tags,split,lower"`
+	}
+	var filter Filter
+	typ := reflect.TypeOf(filter)
+	val := reflect.ValueOf(&filter).Elem()
+	if err := binder.SetValueFromStrings(val.Field(0), []string{"2024-05-01"}, binder.ConvertOptions{Field: typ.Field(0), Tag: "query"}); err != nil || filter.Since.Day() != 1 {
+		t.Fatalf("expected the layout option to apply, got %v, %v", filter.Since, err)
+	}
+	if err := binder.SetValueFromStrings(val.Field(1), []string{"A,B"}, binder.ConvertOptions{Field: typ.Field(1), Tag: "query"}); err != nil || !reflect.DeepEqual(filter.Tags, []string{"a", "b"}) {
+		t.Fatalf("expected the split and lower options to apply, got %v, %v", filter.Tags, err)
+	}
+
+	if err := binder.SetValueFromStrings(reflect.ValueOf(&count).Elem(), []string{"x"}, binder.ConvertOptions{Key: "count"}); err == nil || !strings.Contains(err.Error(), "invalid syntax") {
+		t.Fatalf("expected a conversion error, got %v", err)
+	}
+	if err := binder.SetValueFromStrings(reflect.ValueOf(count), []string{"1"}, binder.ConvertOptions{}); err == nil {
+		t.Fatalf("expected an error for a value that is not settable")
+	}
+}
+
+func TestSetDefaultBinder(t *testing.T) {
+	previous := binder.GetBinder()
+	defer binder.SetDefaultBinder(previous)
+
+	type Search struct {
+		Query string `query:"q"`
+	}
+	b := binder.NewBinder()
+	b.TrimStrings = true
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var data Search
+			if err := binder.BindHttpQueryParams(httptest.NewRequest(http.MethodGet, "/?q=+go+", nil), &data); err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		}()
+	}
+	binder.SetDefaultBinder(b)
+	wg.Wait()
+
+	if binder.GetBinder() != b || binder.GetHttpBinder().Binder != b {
+		t.Fatalf("expected the default binders to use the configured binder")
+	}
+	var data Search
+	if err := binder.BindHttpQueryParams(httptest.NewRequest(http.MethodGet, "/?q=+go+", nil), &data); err != nil || data.Query != "go" {
+		t.Fatalf("expected the configured binder to trim the value, got %q, %v", data.Query, err)
+	}
+}
+
+func TestBindWithContext(t *testing.T) {
+	type User struct {
+		ID   int    `query:"id"`
+		Name string `json:"name" form:"name"`
+	}
+
+	// a slow client sending its body past the deadline
+	for _, contentType := range []string{"application/json", "application/x-www-form-urlencoded"} {
+		body, writer := io.Pipe()
+		defer writer.Close()
+		req := httptest.NewRequest(http.MethodPost, "/?id=1", body)
+		req.ContentLength = 64
+		req.Header.Set("Content-Type", contentType)
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		start := time.Now()
+		var data User
+		err := binder.BindHttpWithContext(ctx, req, &data)
+		cancel()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected the deadline to stop the %s body, got %v", contentType, err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("expected the bind to stop at the deadline, took %v", elapsed)
+		}
+		if data.ID != 1 {
+			t.Fatalf("expected the query to be bound before the body, got %+v", data)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := binder.NewHttpBindableRequest(httptest.NewRequest(http.MethodGet, "/?id=1", nil))
+	var data User
+	if err := binder.BindWithContext(ctx, req, &data); !errors.Is(err, context.Canceled) || data.ID != 0 {
+		t.Fatalf("expected a canceled context to stop the bind, got %+v, %v", data, err)
+	}
+
+	req = binder.NewHttpBindableRequest(httptest.NewRequest(http.MethodPost, "/?id=2", strings.NewReader(`{"name":"John"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	if err := binder.BindWithContext(context.Background(), req, &data); err != nil || data.ID != 2 || data.Name != "John" {
+		t.Fatalf("expected the request to be bound, got %+v, %v", data, err)
+	}
+}
+
+func TestBindWithContextSetsForms(t *testing.T) {
+	type Upload struct {
+		Title string                `form:"title"`
+		File  *multipart.FileHeader `form:"file"`
+	}
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("title", "report")
+	part, _ := writer.CreateFormFile("file", "report.txt")
+	part.Write([]byte("content"))
+	writer.Close()
+	req := httptest.NewRequest(http.MethodPost, "/", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	var upload Upload
+	if err := binder.BindHttpWithContext(context.Background(), req, &upload); err != nil || upload.Title != "report" || upload.File == nil {
+		t.Fatalf("expected the upload to be bound, got %+v, %v", upload, err)
+	}
+	if req.MultipartForm == nil || len(req.MultipartForm.File["file"]) != 1 || req.PostForm.Get("title") != "report" {
+		t.Fatalf("expected the multipart form to be set on the request, got %+v", req.MultipartForm)
+	}
+	req.MultipartForm.RemoveAll()
+
+	req = httptest.NewRequest(http.MethodPost, "/?page=2", strings.NewReader("title=notes"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	upload = Upload{}
+	if err := binder.BindHttpWithContext(context.Background(), req, &upload); err != nil || upload.Title != "notes" {
+		t.Fatalf("expected the form to be bound, got %+v, %v", upload, err)
+	}
+	if req.PostForm.Get("title") != "notes" || req.Form.Get("page") != "2" {
+		t.Fatalf("expected the form to be set on the request, got %v, %v", req.PostForm, req.Form)
+	}
+}
+
+func TestBindWithContextLeavesNoGoroutine(t *testing.T) {
+	type User struct {
+		Name string `json:"name"`
+	}
+	before := runtime.NumGoroutine()
+
+	// a decode error before the end of a body whose client stalls
+	stalled, writer := io.Pipe()
+	defer writer.Close()
+	req := httptest.NewRequest(http.MethodPost, "/", io.MultiReader(strings.NewReader(`{"name":}`), stalled))
+	req.Header.Set("Content-Type", "application/json")
+	req.ContentLength = 64
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := binder.BindHttpWithContext(ctx, req, &User{}); err == nil {
+		t.Fatalf("expected the decode error")
+	}
+
+	// a slow client sending its body past the deadline
+	body, writer := io.Pipe()
+	defer writer.Close()
+	req = httptest.NewRequest(http.MethodPost, "/", body)
+	req.Header.Set("Content-Type", "application/json")
+	req.ContentLength = 64
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := binder.BindHttpWithContext(ctx, req, &User{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline to stop the body, got %v", err)
+	}
+
+	for deadline := time.Now().Add(time.Second); runtime.NumGoroutine() > before; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected no goroutine to outlive the binds, got %d, had %d", runtime.NumGoroutine(), before)
+		}
+	}
+}
+
+func TestParsePathPattern(t *testing.T) {
+	for pattern, expected := range map[string][]binder.ParamSpec{
+		"/users/{id}": {{Name: "id", Position: 1}},
+		"GET example.com/users/{id}/files/{path...}": {{Name: "id", Position: 1}, {Name: "path", Wildcard: true, Position: 3}},
+		"/posts/{slug}/{$}":                          {{Name: "slug", Position: 1}},
+		"/orders/{id:[0-9]+}":                        {{Name: "id", Position: 1}},
+		"api/{version}/items":                        {{Name: "version", Position: 1}},
+		"/health":                                    nil,
+	} {
+		if specs := binder.ParsePathPattern(pattern); !reflect.DeepEqual(specs, expected) {
+			t.Fatalf("expected %+v for %s, got %+v", expected, pattern, specs)
+		}
+	}
+
+	type File struct {
+		ID   string `param:"id"`
+		Path string `param:"path"`
+	}
+	req := httptest.NewRequest(http.MethodGet, "/users/7/files/docs/a.txt", nil)
+	req.Pattern = "GET /users/{id}/files/{path...}"
+	req.SetPathValue("id", "7")
+	req.SetPathValue("path", "docs/a.txt")
+	var data File
+	if err := binder.BindHttpPathParms(req, &data); err != nil || data != (File{ID: "7", Path: "docs/a.txt"}) {
+		t.Fatalf("expected the wildcards to be bound, got %+v, %v", data, err)
+	}
+}
+
+func TestBindFuncCombinators(t *testing.T) {
+	type Webhook struct {
+		Signature string `header:"X-Signature"`
+		Event     string `json:"event"`
+	}
+	b := binder.NewBinder()
+	signed := func(r binder.BindableRequest) bool {
+		return r.GetHeaders().Get("X-Signature") != ""
+	}
+	bind := binder.Sequence(b.BindHeaders, binder.When(signed, b.BindBody))
+
+	newRequest := func(signature string) binder.BindableRequest {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"event":"push"}`))
+		req.Header.Set("Content-Type", "application/json")
+		if signature != "" {
+			req.Header.Set("X-Signature", signature)
+		}
+		return binder.NewHttpBindableRequest(req)
+	}
+
+	var data Webhook
+	if err := bind(newRequest("abc"), &data); err != nil || data != (Webhook{Signature: "abc", Event: "push"}) {
+		t.Fatalf("expected the headers and body to be bound, got %+v, %v", data, err)
+	}
+	data = Webhook{}
+	if err := bind(newRequest(""), &data); err != nil || data.Event != "" {
+		t.Fatalf("expected the body to be skipped without signature, got %+v, %v", data, err)
+	}
+
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+	failing := func(err error) binder.BindFunc {
+		return func(binder.BindableRequest, interface{}) error { return err }
+	}
+	if err := binder.FirstSuccess(failing(errFirst), b.BindBody)(newRequest(""), &data); err != nil || data.Event != "push" {
+		t.Fatalf("expected the second function to succeed, got %+v, %v", data, err)
+	}
+	if err := binder.FirstSuccess(failing(errFirst), failing(errSecond))(newRequest(""), &data); !errors.Is(err, errFirst) || !errors.Is(err, errSecond) {
+		t.Fatalf("expected the errors to be joined, got %v", err)
+	}
+	if err := binder.Sequence(failing(errFirst), failing(errSecond))(newRequest(""), &data); err != errFirst {
+		t.Fatalf("expected the sequence to stop at the first error, got %v", err)
+	}
+}
+
+func TestBindWithOptions(t *testing.T) {
+	type Search struct {
+		Query string `query:"q" json:"q"`
+		Limit int    `query:"limit" json:"limit"`
+		Tags  []int  `query:"tags"`
+	}
+	b := binder.NewBinder()
+	httpBinder := &binder.HttpBinder{Binder: b}
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/?q=query&extra=1", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	var data Search
+	req := httptest.NewRequest(http.MethodGet, "/?tags[5]=1", nil)
+	if err := httpBinder.BindWith(req, &data, binder.WithMaxArraySize(2)); err == nil {
+		t.Fatalf("expected the index to exceed the max array size of the call")
+	}
+	req = httptest.NewRequest(http.MethodGet, "/?tags[5]=1", nil)
+	if err := httpBinder.Bind(req, &data); err != nil || len(data.Tags) != 6 {
+		t.Fatalf("expected the binder to be unchanged, got %+v, %v", data, err)
+	}
+	if err := httpBinder.BindWith(newRequest(`{}`), &data, binder.WithStrict(true)); err == nil {
+		t.Fatalf("expected the unknown query key to fail the strict call")
+	}
+	data = Search{}
+	if err := httpBinder.BindWith(newRequest(`{"q":"body","limit":10}`), &data, binder.Only(binder.SourceQuery)); err != nil || data.Query != "query" || data.Limit != 0 {
+		t.Fatalf("expected only the query to be bound, got %+v, %v", data, err)
+	}
+	if err := httpBinder.BindWith(newRequest(`{}`), &data, binder.Only("cookies")); !errors.Is(err, binder.ErrUnknownSource) {
+		t.Fatalf("expected ErrUnknownSource, got %v", err)
+	}
+	data = Search{}
+	if err := httpBinder.BindBodyWith(newRequest(`{"q":"body"}`), &data, binder.WithStrict(true)); err != nil || data.Query != "body" {
+		t.Fatalf("expected the body to be bound, got %+v, %v", data, err)
+	}
+}
+
+func TestBindMaxBodySize(t *testing.T) {
+	type Upload struct {
+		Name string                `json:"name" form:"name"`
+		File *multipart.FileHeader `form:"file"`
+	}
+	httpBinder := &binder.HttpBinder{Binder: binder.NewBinder()}
+	large := strings.Repeat("x", 1024)
+	var multipartBody bytes.Buffer
+	mw := multipart.NewWriter(&multipartBody)
+	mw.WriteField("name", "report")
+	part, _ := mw.CreateFormFile("file", "report.txt")
+	part.Write([]byte(large))
+	mw.Close()
+	newRequest := func(contentType string, body string, chunked bool) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		if chunked {
+			req.ContentLength = -1
+		}
+		return req
+	}
+
+	for _, chunked := range []bool{false, true} {
+		for contentType, body := range map[string]string{
+			"application/json":                  `{"name":"` + large + `"}`,
+			"application/x-www-form-urlencoded": "name=" + large,
+			mw.FormDataContentType():            multipartBody.String(),
+		} {
+			var sizeErr *binder.BodySizeError
+			err := httpBinder.BindWith(newRequest(contentType, body, chunked), &Upload{}, binder.WithMaxBodySize(16))
+			if !errors.As(err, &sizeErr) || sizeErr.MaxBodySize != 16 || !errors.Is(err, binder.ErrBodyTooLarge) {
+				t.Fatalf("expected a body size error for %s (chunked %v), got %v", contentType, chunked, err)
+			}
+		}
+	}
+
+	var data Upload
+	if err := httpBinder.BindWith(newRequest("application/json", `{"name":"abcdef"}`, true), &data, binder.WithMaxBodySize(17)); err != nil || data.Name != "abcdef" {
+		t.Fatalf("expected a body of the max body size to be bound, got %+v, %v", data, err)
+	}
+	data = Upload{}
+	if err := httpBinder.BindWith(newRequest(mw.FormDataContentType(), multipartBody.String(), true), &data, binder.WithMaxBodySize(0)); err != nil || data.File == nil {
+		t.Fatalf("expected no limit with a max body size of 0, got %+v, %v", data, err)
+	}
+}
+
+func TestBindNamedSources(t *testing.T) {
+	type Labels struct {
+		First  string
+		Second string
+	}
+	// closures of the same function literal share their code pointer, the sources are told apart by name
+	label := func(value string) binder.BindFunc {
+		return func(r binder.BindableRequest, i interface{}) error {
+			labels := i.(*Labels)
+			if labels.First == "" {
+				labels.First = value
+			} else {
+				labels.Second = value
+			}
+			return nil
+		}
+	}
+	b := binder.NewBinder()
+	b.RegisterBindSource("a", label("a"))
+	b.RegisterBindSource("b", label("b"))
+	b.BindOrder = []string{"a", "b"}
+	req := binder.NewHttpBindableRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var data Labels
+	var report binder.BindReport
+	if err := b.BindWith(req, &data, binder.WithReport(&report)); err != nil || data != (Labels{First: "a", Second: "b"}) {
+		t.Fatalf("expected both sources to be bound, got %+v, %v", data, err)
+	}
+	if report.Source("First") != "a" || report.Source("Second") != "b" {
+		t.Fatalf("expected the sources to be reported by name, got %v", report.Fields)
+	}
+	data = Labels{}
+	if err := b.BindWith(req, &data, binder.Only("b")); err != nil || data != (Labels{First: "b"}) {
+		t.Fatalf("expected only the source b to be bound, got %+v, %v", data, err)
+	}
+	b.BindOrder = append(b.BindOrder, "unknown")
+	if err := b.Bind(req, &Labels{}); !errors.Is(err, binder.ErrUnknownSource) {
+		t.Fatalf("expected ErrUnknownSource for an unknown name in BindOrder, got %v", err)
+	}
+}
+
+func TestBindHooks(t *testing.T) {
+	type Order struct {
+		Tenant string `query:"tenant"`
+		Status string `query:"status"`
+	}
+	b := binder.NewBinder()
+	calls := []string{}
+	b.OnBeforeBind(func(r binder.BindableRequest, i interface{}) error {
+		calls = append(calls, "before")
+		if r.GetHeaders().Get("X-Tenant") == "" {
+			return errors.New("missing tenant")
+		}
+		return nil
+	})
+	b.OnAfterBind(func(r binder.BindableRequest, i interface{}) error {
+		calls = append(calls, "after")
+		order := i.(*Order)
+		order.Tenant = r.GetHeaders().Get("X-Tenant")
+		if order.Status == "" {
+			order.Status = "open"
+		}
+		return nil
+	})
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	var data Order
+	req := httptest.NewRequest(http.MethodGet, "/?tenant=other", nil)
+	req.Header.Set("X-Tenant", "acme")
+	if err := httpBinder.Bind(req, &data); err != nil || data != (Order{Tenant: "acme", Status: "open"}) {
+		t.Fatalf("expected the hooks to scope and default the destination, got %+v, %v", data, err)
+	}
+	data = Order{}
+	req = httptest.NewRequest(http.MethodGet, "/?status=closed", nil)
+	req.Header.Set("X-Tenant", "acme")
+	if err := httpBinder.BindSelected(req, &data, binder.SourceQuery); err != nil || data != (Order{Tenant: "acme", Status: "closed"}) {
+		t.Fatalf("expected the hooks to run around BindSelected, got %+v, %v", data, err)
+	}
+	calls = calls[:0]
+	data = Order{}
+	req = httptest.NewRequest(http.MethodGet, "/?status=closed", nil)
+	if err := httpBinder.Bind(req, &data); err == nil || data.Status != "" {
+		t.Fatalf("expected the before hook to stop the bind, got %+v, %v", data, err)
+	}
+	if len(calls) != 1 || calls[0] != "before" {
+		t.Fatalf("expected the after hook to be skipped, got %v", calls)
+	}
+}
+
+type SelfBindingSearch struct {
+	Query string `query:"q"`
+	Terms []string
+	Body  string `json:"body"`
+}
+
+func (s *SelfBindingSearch) BindRequest(r binder.BindableRequest, b binder.Binder) error {
+	if err := b.BindQueryParams(r, s); err != nil {
+		return err
+	}
+	s.Terms = strings.Fields(s.Query)
+	return nil
+}
+
+func TestBindBindable(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/?q=go+binder", strings.NewReader(`{"body":"ignored"}`))
+	req.Header.Set("Content-Type", "application/json")
+	var data SelfBindingSearch
+	if err := binder.BindHttp(req, &data); err != nil {
+		t.Fatal(err)
+	}
+	if data.Query != "go binder" || len(data.Terms) != 2 || data.Body != "" {
+		t.Fatalf("expected the destination to bind itself from the query, got %+v", data)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?q=a+b+c", nil)
+	data = SelfBindingSearch{}
+	if err := binder.BindHttpWithContext(context.Background(), req, &data); err != nil || len(data.Terms) != 3 {
+		t.Fatalf("expected BindWithContext to delegate to the destination, got %+v, %v", data, err)
+	}
+}
+
+type NestedBindingSearch struct {
+	SelfBindingSearch
+	Nested error
+}
+
+func (s *NestedBindingSearch) BindRequest(r binder.BindableRequest, b binder.Binder) error {
+	// binding the destination again while it is being bound
+	s.Nested = b.Bind(r, s)
+	return s.SelfBindingSearch.BindRequest(r, b)
+}
+
+func TestBindBindableHooks(t *testing.T) {
+	b := binder.NewBinder()
+	var calls []string
+	b.OnBeforeBind(func(r binder.BindableRequest, i interface{}) error {
+		calls = append(calls, "before:"+i.(*SelfBindingSearch).Query)
+		return nil
+	})
+	b.OnAfterBind(func(r binder.BindableRequest, i interface{}) error {
+		calls = append(calls, "after:"+i.(*SelfBindingSearch).Query)
+		return nil
+	})
+	req := binder.NewHttpBindableRequest(httptest.NewRequest(http.MethodGet, "/?q=go+binder", nil))
+	var data SelfBindingSearch
+	if err := b.Bind(req, &data); err != nil || len(data.Terms) != 2 {
+		t.Fatalf("expected the destination to bind itself, got %+v, %v", data, err)
+	}
+	if strings.Join(calls, ",") != "before:,after:go binder" {
+		t.Fatalf("expected the hooks to run around BindRequest, got %v", calls)
+	}
+
+	b = binder.NewBinder()
+	b.DetectConcurrentBinds = true
+	var nested NestedBindingSearch
+	if err := b.Bind(req, &nested); err != nil || !errors.Is(nested.Nested, binder.ErrConcurrentBind) {
+		t.Fatalf("expected the destination to be guarded while it binds itself, got %v, %v", nested.Nested, err)
+	}
+	if err := b.Bind(req, &nested); err != nil {
+		t.Fatalf("expected the guard to be released after the bind, got %v", err)
+	}
+}
+
+func TestBindReport(t *testing.T) {
+	type Address struct {
+		City string `query:"city" json:"city"`
+	}
+	type Profile struct {
+		Name    string   `query:"name" json:"name"`
+		Tags    []string `query:"tags" json:"tags"`
+		Address Address  `query:"address" json:"address"`
+		Age     int      `json:"age"`
+	}
+	b := binder.NewBinder()
+	req := httptest.NewRequest(http.MethodPost, "/?name=query&tags=a&address.city=Rome", strings.NewReader(`{"name":"body","age":0}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	var data Profile
+	var report binder.BindReport
+	if err := b.BindWith(binder.NewHttpBindableRequest(req), &data, binder.WithReport(&report)); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"Name": binder.SourceBody, "Tags": binder.SourceQuery, "Address.City": binder.SourceQuery}
+	if !reflect.DeepEqual(report.Fields, expected) {
+		t.Fatalf("expected %v, got %v", expected, report.Fields)
+	}
+	if report.Source("Age") != "" {
+		t.Fatalf("expected the unchanged field not to be reported, got %q", report.Source("Age"))
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"age":30}`))
+	req.Header.Set("Content-Type", "application/json")
+	report = binder.BindReport{}
+	if err := b.BindBodyWith(binder.NewHttpBindableRequest(req), &data, binder.WithReport(&report)); err != nil || report.Source("Age") != binder.SourceBody {
+		t.Fatalf("expected the body to be reported, got %v, %v", report.Fields, err)
+	}
+}
+
+func TestBindArraySizeTruncate(t *testing.T) {
+	type Batch struct {
+		IDs []int `query:"ids"`
+	}
+	b := binder.NewBinder()
+	b.MaxArraySize = 2
+	b.ArraySizePolicy = binder.ArraySizeTruncate
+
+	req := httptest.NewRequest(http.MethodGet, "/?ids[0]=1&ids[1]=2&ids[2]=3&ids[9]=10", nil)
+	var data Batch
+	var report binder.BindReport
+	if err := b.BindWith(binder.NewHttpBindableRequest(req), &data, binder.WithReport(&report)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(data.IDs, []int{1, 2}) {
+		t.Fatalf("expected the arrays to be truncated to 2 elements, got %+v", data)
+	}
+	if len(report.Warnings) != 1 || !strings.Contains(report.Warnings[0], "ids") {
+		t.Fatalf("expected a warning for ids, got %v", report.Warnings)
+	}
+
+	b.ArraySizePolicy = binder.ArraySizeError
+	req = httptest.NewRequest(http.MethodGet, "/?ids[9]=10", nil)
+	if err := b.Bind(binder.NewHttpBindableRequest(req), &Batch{}); err == nil {
+		t.Fatalf("expected an error for the index exceeding the max array size")
+	}
+	req = httptest.NewRequest(http.MethodGet, "/?ids[2]=3", nil)
+	if err := b.Bind(binder.NewHttpBindableRequest(req), &Batch{}); err == nil {
+		t.Fatalf("expected an error for a third element with a max array size of 2")
+	}
+	req = httptest.NewRequest(http.MethodGet, "/?ids[1]=2", nil)
+	if err := b.Bind(binder.NewHttpBindableRequest(req), &data); err != nil || len(data.IDs) != 2 {
+		t.Fatalf("expected two elements to be bound, got %+v, %v", data, err)
+	}
+}
+
+func TestBindReportPresence(t *testing.T) {
+	type Address struct {
+		City string `json:"city" form:"city"`
+		Zip  string `json:"zip" form:"zip"`
+	}
+	type ProfilePatch struct {
+		Name    string   `json:"name" form:"name"`
+		Age     *int     `json:"age" form:"age"`
+		Address *Address `json:"address" form:"address"`
+		Email   string   `json:"email" form:"email"`
+	}
+	b := binder.NewBinder()
+	req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"name":"","AGE":0,"address":{"city":""}}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	var data ProfilePatch
+	var report binder.BindReport
+	if err := b.BindBodyWith(binder.NewHttpBindableRequest(req), &data, binder.WithReport(&report)); err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"Name", "Age", "Address", "Address.City"} {
+		if !report.WasSet(field) {
+			t.Fatalf("expected %s to be set, got %v", field, report.Present)
+		}
+	}
+	if report.WasSet("Email") || report.WasSet("Address.Zip") {
+		t.Fatalf("expected the omitted fields not to be set, got %v", report.Present)
+	}
+	if len(report.Fields) != 1 || report.Source("Age") != binder.SourceBody {
+		t.Fatalf("expected only the changed fields to be reported, got %v", report.Fields)
+	}
+
+	req = httptest.NewRequest(http.MethodPatch, "/", strings.NewReader("name=&address.zip="))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	data = ProfilePatch{}
+	report = binder.BindReport{}
+	if err := b.BindBodyWith(binder.NewHttpBindableRequest(req), &data, binder.WithReport(&report)); err != nil {
+		t.Fatal(err)
+	}
+	if !report.WasSet("Name") || !report.WasSet("Address.Zip") || report.WasSet("Age") || report.WasSet("Address.City") {
+		t.Fatalf("expected the supplied form keys to be set, got %v", report.Present)
+	}
+}
+
+func TestBindDetectConcurrentBinds(t *testing.T) {
+	type Query struct {
+		Q string `query:"q"`
+	}
+	b := binder.NewBinder()
+	b.DetectConcurrentBinds = true
+	started := make(chan struct{})
+	proceed := make(chan struct{})
+	b.OnBeforeBind(func(r binder.BindableRequest, i interface{}) error {
+		if r.GetHeaders().Get("X-Block") != "" {
+			close(started)
+			<-proceed
+		}
+		return nil
+	})
+
+	var shared Query
+	done := make(chan error)
+	go func() {
+		req := httptest.NewRequest(http.MethodGet, "/?q=first", nil)
+		req.Header.Set("X-Block", "1")
+		done <- b.Bind(binder.NewHttpBindableRequest(req), &shared)
+	}()
+	<-started
+	req := httptest.NewRequest(http.MethodGet, "/?q=second", nil)
+	if err := b.Bind(binder.NewHttpBindableRequest(req), &shared); !errors.Is(err, binder.ErrConcurrentBind) {
+		t.Fatalf("expected ErrConcurrentBind, got %v", err)
+	}
+	var other Query
+	if err := b.Bind(binder.NewHttpBindableRequest(req), &other); err != nil || other.Q != "second" {
+		t.Fatalf("expected another destination to be bound, got %+v, %v", other, err)
+	}
+	close(proceed)
+	if err := <-done; err != nil || shared.Q != "first" {
+		t.Fatalf("expected the first bind to succeed, got %+v, %v", shared, err)
+	}
+	if err := b.Bind(binder.NewHttpBindableRequest(req), &shared); err != nil {
+		t.Fatalf("expected the destination to be released, got %v", err)
+	}
+}
+
+func TestBindOptional(t *testing.T) {
+	type Filter struct {
+		Age      binder.Optional[int]       `query:"age" json:"age"`
+		Name     binder.Optional[string]    `query:"name" json:"name"`
+		Tags     binder.Optional[[]string]  `query:"tags" json:"tags"`
+		Since    binder.Optional[time.Time] `query:"since" json:"since"`
+		Nickname binder.Optional[string]    `query:"nickname" json:"nickname"`
+	}
+	req := httptest.NewRequest(http.MethodGet, "/?age=0&name=&tags=a&tags=b&since=2024-01-02T03:04:05Z", nil)
+	var data Filter
+	if err := binder.BindHttp(req, &data); err != nil {
+		t.Fatal(err)
+	}
+	if age, ok := data.Age.Get(); !ok || age != 0 {
+		t.Fatalf("expected age to be present, got %+v", data.Age)
+	}
+	if !data.Name.Present || !reflect.DeepEqual(data.Tags.Value, []string{"a", "b"}) || data.Since.Value.Year() != 2024 {
+		t.Fatalf("expected the query values to be bound, got %+v", data)
+	}
+	if data.Nickname.Present {
+		t.Fatalf("expected nickname to be absent, got %+v", data.Nickname)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?age=abc", nil)
+	if err := binder.BindHttp(req, &Filter{}); err == nil {
+		t.Fatalf("expected a conversion error")
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"age":null,"name":"bob"}`))
+	req.Header.Set("Content-Type", "application/json")
+	data = Filter{Age: binder.Optional[int]{Value: 3}}
+	if err := binder.BindHttp(req, &data); err != nil {
+		t.Fatal(err)
+	}
+	if !data.Age.Present || data.Age.Value != 0 || data.Name.Value != "bob" || data.Tags.Present {
+		t.Fatalf("expected the JSON keys to be present, got %+v", data)
+	}
+	out, err := json.Marshal(data)
+	if err != nil || !strings.Contains(string(out), `"tags":null`) || !strings.Contains(string(out), `"name":"bob"`) {
+		t.Fatalf("expected absent values to be encoded as null, got %s, %v", out, err)
+	}
+}
+
+func TestBindReportStats(t *testing.T) {
+	type Upload struct {
+		Title string                `query:"title" form:"title"`
+		Token string                `header:"X-Token"`
+		File  *multipart.FileHeader `form:"file"`
+	}
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	writer.WriteField("title", "a longer title")
+	part, _ := writer.CreateFormFile("file", "a.txt")
+	part.Write([]byte("content"))
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/?title=short&page=1", bytes.NewReader(body.Bytes()))
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("X-Token", "abc")
+
+	b := binder.NewBinder()
+	b.BindOrder = append(b.BindOrder, binder.SourceHeader)
+	var data Upload
+	var report binder.BindReport
+	if err := b.BindWith(binder.NewHttpBindableRequest(req), &data, binder.WithReport(&report)); err != nil {
+		t.Fatal(err)
+	}
+	stats := report.Stats
+	if stats.Keys[binder.SourceQuery] != 2 || stats.Keys[binder.SourceBody] != 2 || stats.Keys[binder.SourceHeader] == 0 {
+		t.Fatalf("expected the keys to be counted by source, got %v", stats.Keys)
+	}
+	if stats.Files != 1 || stats.MaxValueLength != len(writer.FormDataContentType()) || stats.BodyBytes != int64(body.Len()) {
+		t.Fatalf("expected the files, value length and body size to be recorded, got %+v", stats)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"title":"json"}`))
+	req.Header.Set("Content-Type", "application/json")
+	report = binder.BindReport{}
+	if err := b.BindBodyWith(binder.NewHttpBindableRequest(req), &data, binder.WithReport(&report)); err != nil || report.Stats.BodyBytes != 16 {
+		t.Fatalf("expected the JSON body size to be recorded, got %+v, %v", report.Stats, err)
+	}
+}
+
+func TestBindMergePolicy(t *testing.T) {
+	type Item struct {
+		Name  string `query:"name" json:"name"`
+		Color string `query:"color" json:"color"`
+		Size  int    `query:"size" json:"size"`
+	}
+	newRequest := func() binder.BindableRequest {
+		req := httptest.NewRequest(http.MethodPost, "/?name=query&size=1", strings.NewReader(`{"name":"body","color":"red"}`))
+		req.Header.Set("Content-Type", "application/json")
+		return binder.NewHttpBindableRequest(req)
+	}
+	b := binder.NewBinder()
+
+	var data Item
+	if err := b.Bind(newRequest(), &data); err != nil || data.Name != "body" {
+		t.Fatalf("expected the last source to win, got %+v, %v", data, err)
+	}
+
+	b.MergePolicy = binder.MergeFirstWins
+	data = Item{}
+	if err := b.Bind(newRequest(), &data); err != nil || data != (Item{Name: "query", Color: "red", Size: 1}) {
+		t.Fatalf("expected the first source to win, got %+v, %v", data, err)
+	}
+
+	b.MergePolicy = binder.MergeSkipIfNonZero
+	data = Item{Color: "blue"}
+	if err := b.Bind(newRequest(), &data); err != nil || data != (Item{Name: "query", Color: "blue", Size: 1}) {
+		t.Fatalf("expected the non-zero fields to be kept, got %+v, %v", data, err)
+	}
+
+	b.MergePolicy = binder.MergeErrorOnConflict
+	data = Item{}
+	var conflict *binder.MergeConflictError
+	err := b.Bind(newRequest(), &data)
+	if !errors.As(err, &conflict) || conflict.Field != "Name" || conflict.Previous != binder.SourceQuery || conflict.Source != binder.SourceBody {
+		t.Fatalf("expected a conflict on Name, got %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/?name=same", strings.NewReader(`{"name":"same"}`))
+	req.Header.Set("Content-Type", "application/json")
+	if err := b.Bind(binder.NewHttpBindableRequest(req), &Item{}); err != nil {
+		t.Fatalf("expected equal values not to conflict, got %v", err)
+	}
+}
+
+func TestBindSensitiveRedaction(t *testing.T) {
+	type Login struct {
+		User string `query:"user"`
+		PIN  int    `query:"pin" sensitive:"true"`
+		OTPs []int  `query:"otp" sensitive:"true"`
+		Age  int    `query:"age"`
+	}
+	req := httptest.NewRequest(http.MethodGet, "/?user=bob&pin=12ab34", nil)
+	err := binder.BindHttp(req, &Login{})
+	if err == nil || strings.Contains(err.Error(), "12ab34") || !strings.Contains(err.Error(), binder.RedactedValue) {
+		t.Fatalf("expected the pin to be redacted, got %v", err)
+	}
+	var sensitiveErr *binder.SensitiveFieldError
+	if !errors.As(err, &sensitiveErr) || sensitiveErr.Field != "pin" || sensitiveErr.Kind != reflect.Int {
+		t.Fatalf("expected a sensitive field error for pin, got %v", err)
+	}
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		t.Fatalf("expected the original error not to be wrapped, got %v", numErr)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?otp=1&otp=hunter2", nil)
+	err = binder.BindHttp(req, &Login{})
+	if err == nil || errors.As(err, &numErr) {
+		t.Fatalf("expected the otp error not to wrap the conversion error, got %v", err)
+	}
+	for chain := err; chain != nil; chain = errors.Unwrap(chain) {
+		if strings.Contains(chain.Error(), "hunter2") {
+			t.Fatalf("expected no error of the chain to hold the otp, got %v", chain)
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?age=old", nil)
+	if err := binder.BindHttp(req, &Login{}); err == nil || !strings.Contains(err.Error(), "old") {
+		t.Fatalf("expected other values to be kept, got %v", err)
+	}
+}
+
+func TestBindHeadersUseHeaderTag(t *testing.T) {
+	type Request struct {
+		RequestID string `header:"x-request-id" form:"request_id"`
+		Trace     string `header:"X-Trace" form:"x-trace"`
+	}
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("request_id=form&X-Trace=form"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Request-ID", "header")
+
+	b := binder.NewBinder()
+	b.CompatLevel = binder.CompatV2
+	httpBinder := &binder.HttpBinder{Binder: b}
+	var data Request
+	if err := httpBinder.BindHeaders(req, &data); err != nil || data != (Request{RequestID: "header"}) {
+		t.Fatalf("expected only the header mapping to be bound, case-insensitive, got %+v, %v", data, err)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/?X-Trace=query", strings.NewReader("X-Trace=form"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Request-ID", "header")
+	req.Header.Set("X-Trace", "header")
+	data = Request{}
+	if err := (&binder.HttpBinder{Binder: binder.NewBinder()}).BindWith(req, &data, binder.WithBindHeaders(true)); err != nil || data != (Request{RequestID: "header", Trace: "form"}) {
+		t.Fatalf("expected the headers to be bound by Bind before the body, got %+v, %v", data, err)
+	}
+
+	b = binder.NewBinder()
+	before := len(b.BindOrder)
+	binder.WithBindHeaders(true)(b)
+	binder.WithBindHeaders(true)(b)
+	if len(b.BindOrder) != before+1 {
+		t.Fatalf("expected BindHeaders to be added once, got %d steps", len(b.BindOrder))
+	}
+	var ordered struct {
+		Value string `query:"v" header:"X-V"`
+	}
+	req = httptest.NewRequest(http.MethodGet, "/?v=query", nil)
+	req.Header.Set("X-V", "header")
+	if err := (&binder.HttpBinder{Binder: b}).Bind(req, &ordered); err != nil || ordered.Value != "header" {
+		t.Fatalf("expected the headers to be bound after the query, got %+v, %v", ordered, err)
+	}
+}
+
+func TestBindMaxBytes(t *testing.T) {
+	type Comment struct {
+		Title string   `query:"title" json:"title" maxbytes:"5"`
+		Tags  []string `query:"tags" json:"tags" maxbytes:"3"`
+		Body  *string  `query:"body" json:"body,maxbytes=4"`
+	}
+	req := httptest.NewRequest(http.MethodGet, "/?title=toolong", nil)
+	var sizeErr *binder.FieldSizeError
+	if err := binder.BindHttp(req, &Comment{}); !errors.As(err, &sizeErr) || sizeErr.Field != "title" || sizeErr.Max != 5 {
+		t.Fatalf("expected a *FieldSizeError for title, got %v", err)
+	}
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"body":"too long"}`))
+	req.Header.Set("Content-Type", "application/json")
+	if err := binder.BindHttp(req, &Comment{}); !errors.As(err, &sizeErr) || sizeErr.Field != "body" || sizeErr.Max != 4 {
+		t.Fatalf("expected a *FieldSizeError for the body field, got %v", err)
+	}
+
+	b := binder.NewBinder()
+	b.StringSizePolicy = binder.StringSizeTruncate
+	req = httptest.NewRequest(http.MethodGet, "/?title=caf%C3%A9s!&tags=abcd&tags=ab&tags[5]=xyzw", nil)
+	var data Comment
+	var report binder.BindReport
+	if err := b.BindWith(binder.NewHttpBindableRequest(req), &data, binder.WithReport(&report)); err != nil {
+		t.Fatal(err)
+	}
+	if data.Title != "café" || data.Tags[0] != "abc" || data.Tags[1] != "ab" {
+		t.Fatalf("expected the values to be truncated on character boundaries, got %+v", data)
+	}
+	if len(report.Warnings) == 0 {
+		t.Fatalf("expected the truncations to be reported")
+	}
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"tags":["abcdef"],"body":"too long"}`))
+	req.Header.Set("Content-Type", "application/json")
+	data = Comment{}
+	if err := b.BindBody(binder.NewHttpBindableRequest(req), &data); err != nil || data.Tags[0] != "abc" || *data.Body != "too " {
+		t.Fatalf("expected the decoded values to be truncated, got %+v, %v", data, err)
+	}
+}
+
+func TestBindMaxBytesInvalidTag(t *testing.T) {
+	type Comment struct {
+		Title string `query:"title" json:"title" maxbytes:"many"`
+	}
+	req := httptest.NewRequest(http.MethodGet, "/?title=x", nil)
+	if err := binder.BindHttp(req, &Comment{}); err == nil || !strings.Contains(err.Error(), "invalid maxbytes") {
+		t.Fatalf("expected an invalid maxbytes error from the query, got %v", err)
+	}
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"title":"x"}`))
+	req.Header.Set("Content-Type", "application/json")
+	if err := binder.BindHttpBody(req, &Comment{}); err == nil || !strings.Contains(err.Error(), "invalid maxbytes") {
+		t.Fatalf("expected an invalid maxbytes error from the body, got %v", err)
+	}
+}
+
+func TestBindSignedNumbers(t *testing.T) {
+	type Move struct {
+		Offset int            `query:"offset" header:"X-Offset"`
+		Delta  *int8          `query:"delta" header:"X-Delta"`
+		Count  uint           `query:"count"`
+		Scale  float64        `query:"scale"`
+		Steps  map[int]string `query:"steps"`
+	}
+	req := httptest.NewRequest(http.MethodGet, "/?offset=-10&delta=%2B5&count=%2B3&scale=-.5&steps[-1]=back&steps[%2B1]=forward", nil)
+	var data Move
+	if err := binder.BindHttp(req, &data); err != nil {
+		t.Fatal(err)
+	}
+	if data.Offset != -10 || *data.Delta != 5 || data.Count != 3 || data.Scale != -0.5 || data.Steps[-1] != "back" || data.Steps[1] != "forward" {
+		t.Fatalf("expected the signed numbers to be bound, got %+v", data)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Offset", "-7")
+	req.Header.Set("X-Delta", "+2")
+	data = Move{}
+	if err := binder.BindHttpHeaders(req, &data); err != nil || data.Offset != -7 || *data.Delta != 2 {
+		t.Fatalf("expected the signed header values to be bound, got %+v, %v", data, err)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?delta=+5&scale=+1.5&steps[+2]=up", nil)
+	if err := binder.BindHttp(req, &Move{}); err == nil {
+		t.Fatalf("expected the decoded spaces to be rejected by default")
+	}
+	b := binder.NewBinder()
+	b.SpaceAsPlusSign = true
+	data = Move{}
+	if err := (&binder.HttpBinder{Binder: b}).Bind(req, &data); err != nil || *data.Delta != 5 || data.Scale != 1.5 || data.Steps[2] != "up" {
+		t.Fatalf("expected the spaces to be read as plus signs, got %+v, %v", data, err)
+	}
+}
+
+func TestBindHeaderPrefixCapture(t *testing.T) {
+	type Proxy struct {
+		Meta    map[string]string   `header:"X-Meta-*"`
+		Forward map[string][]string `header:"x-forward-*"`
+		Region  string              `header:"X-Meta-Region"`
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Meta-Region", "eu")
+	req.Header.Set("X-Meta-Owner", "team")
+	req.Header.Add("X-Forward-Tag", "a")
+	req.Header.Add("X-Forward-Tag", "b")
+	req.Header.Set("X-Other", "ignored")
+
+	var data Proxy
+	if err := binder.BindHttpHeaders(req, &data); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(data.Meta, map[string]string{"Region": "eu", "Owner": "team"}) || data.Region != "eu" {
+		t.Fatalf("expected the prefixed headers to be captured, got %+v", data)
+	}
+	if !reflect.DeepEqual(data.Forward, map[string][]string{"Tag": {"a", "b"}}) {
+		t.Fatalf("expected the repeated header to be captured case-insensitively, got %+v", data.Forward)
+	}
+
+	type Invalid struct {
+		Meta map[string]int `header:"X-Meta-*"`
+	}
+	if err := binder.BindHttpHeaders(req, &Invalid{}); err == nil {
+		t.Fatalf("expected an error for a map of ints")
+	}
+}
+
+func TestBindURLValuesField(t *testing.T) {
+	type Forward struct {
+		url.Values `query:"*"`
+		Page       int `query:"page"`
+	}
+	type FilteredForward struct {
+		Filters url.Values `query:"filter.*" form:"filter.*"`
+	}
+	req := httptest.NewRequest(http.MethodGet, "/?page=2&q=go&q=binder&filter.lang=en", nil)
+	var data Forward
+	if err := binder.BindHttp(req, &data); err != nil {
+		t.Fatal(err)
+	}
+	if data.Page != 2 || data.Get("page") != "2" || !reflect.DeepEqual(data.Values["q"], []string{"go", "binder"}) || data.Get("filter.lang") != "en" {
+		t.Fatalf("expected the embedded url.Values to receive every query value, got %+v", data)
+	}
+
+	b := binder.NewBinder()
+	b.StrictKeys = true
+	httpBinder := &binder.HttpBinder{Binder: b}
+	var filtered FilteredForward
+	if err := httpBinder.BindQueryParams(req, &filtered); err == nil {
+		t.Fatalf("expected the keys outside the prefix to be unknown")
+	}
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("filter.lang=en&filter.sort=asc"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	filtered = FilteredForward{}
+	if err := httpBinder.Bind(req, &filtered); err != nil || filtered.Filters.Encode() != "lang=en&sort=asc" {
+		t.Fatalf("expected the prefixed form values to be captured, got %+v, %v", filtered, err)
+	}
+	data = Forward{}
+	req = httptest.NewRequest(http.MethodGet, "/?anything=1", nil)
+	if err := httpBinder.Bind(req, &data); err != nil || data.Get("anything") != "1" {
+		t.Fatalf("expected every key to be known to a whole capture, got %+v, %v", data, err)
+	}
+}
+
+func TestBindQueryPrefixCapture(t *testing.T) {
+	type Campaign struct {
+		UTM  map[string]string `query:"utm_*"`
+		Page string            `query:"page"`
+	}
+	req := httptest.NewRequest(http.MethodGet, "/?utm_source=news&utm_medium=email&UTM_Campaign=spring&page=home", nil)
+	b := binder.NewBinder()
+	b.StrictKeys = true
+	var data Campaign
+	if err := (&binder.HttpBinder{Binder: b}).BindQueryParams(req, &data); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"source": "news", "medium": "email", "Campaign": "spring"}
+	if !reflect.DeepEqual(data.UTM, expected) || data.Page != "home" {
+		t.Fatalf("expected %v, got %+v", expected, data)
+	}
+
+	b.CompatLevel = binder.CompatV2
+	data = Campaign{}
+	if err := (&binder.HttpBinder{Binder: b}).BindQueryParams(req, &data); err == nil {
+		t.Fatalf("expected the key with another case to be unknown with CompatV2, got %+v", data)
+	}
+}
+
+func TestBindEnvelopeField(t *testing.T) {
+	type Order struct {
+		ID     int    `json:"id"`
+		Status string `json:"status" bind:"-"`
+	}
+	type Webhook struct {
+		Payload   Order  `form:"payload,json"`
+		Signature string `form:"signature"`
+		Items     []int  `form:"items,json"`
+	}
+	form := url.Values{}
+	form.Set("payload", `{"id":42,"status":"paid"}`)
+	form.Set("signature", "sha256=abc")
+	form.Set("items", `[1,2]`)
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var data Webhook
+	if err := binder.BindHttp(req, &data); err != nil {
+		t.Fatal(err)
+	}
+	if data.Payload.ID != 42 || data.Payload.Status != "" || data.Signature != "sha256=abc" || !reflect.DeepEqual(data.Items, []int{1, 2}) {
+		t.Fatalf("expected the payload to be decoded from JSON, got %+v", data)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("payload=%7Bnot+json"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if err := binder.BindHttp(req, &Webhook{}); err == nil || !strings.Contains(err.Error(), "payload") {
+		t.Fatalf("expected a JSON error for the payload, got %v", err)
+	}
+}
+
+func TestBindEnvelopeFieldOptions(t *testing.T) {
+	type Order struct {
+		ID    int               `json:"id"`
+		Note  string            `json:"note" maxbytes:"4"`
+		Extra map[string]string `json:"-" bind:",unknown"`
+	}
+	type Webhook struct {
+		Payload Order `form:"payload,json"`
+	}
+	newRequest := func(payload string) *http.Request {
+		form := url.Values{"payload": {payload}}
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req
+	}
+	httpBinder := &binder.HttpBinder{Binder: binder.NewBinder()}
+
+	var data Webhook
+	if err := httpBinder.Bind(newRequest(`{"id":1,"note":"ok","channel":"web"}`), &data); err != nil || data.Payload.ID != 1 || data.Payload.Extra["channel"] != "web" {
+		t.Fatalf("expected the unknown payload keys to be captured, got %+v, %v", data, err)
+	}
+	var sizeErr *binder.FieldSizeError
+	if err := httpBinder.Bind(newRequest(`{"id":1,"note":"too long"}`), &Webhook{}); !errors.As(err, &sizeErr) || sizeErr.Field != "note" {
+		t.Fatalf("expected the maxbytes of the payload to be checked, got %v", err)
+	}
+
+	type StrictOrder struct {
+		ID int `json:"id"`
+	}
+	type StrictWebhook struct {
+		Payload StrictOrder `form:"payload,json"`
+	}
+	if err := httpBinder.BindWith(newRequest(`{"id":1,"channel":"web"}`), &StrictWebhook{}, binder.WithStrict(true)); err == nil || !strings.Contains(err.Error(), "channel") {
+		t.Fatalf("expected the strict JSON decoder to reject the unknown payload key, got %v", err)
+	}
+	if err := httpBinder.Bind(newRequest(`{"id":1,"channel":"web"}`), &StrictWebhook{}); err != nil {
+		t.Fatalf("expected the unknown payload key to be ignored, got %v", err)
+	}
+}
+
+func TestBindRawBody(t *testing.T) {
+	type Event struct {
+		ID        int            `json:"id"`
+		Raw       binder.RawBody `json:"-"`
+		Signature string         `query:"signature"`
+	}
+	body := `{"id":7}`
+	req := httptest.NewRequest(http.MethodPost, "/?signature=sha256%3Dabc", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	var event Event
+	if err := binder.BindHttp(req, &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.ID != 7 || string(event.Raw) != body || event.Signature != "sha256=abc" {
+		t.Fatalf("expected the raw body alongside the decoded fields, got %+v", event)
+	}
+
+	type Form struct {
+		Name string `form:"name"`
+		Raw  []byte `body:"raw"`
+	}
+	req = httptest.NewRequest(http.MethodPost, "/?raw=query", strings.NewReader("name=bob"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	var form Form
+	if err := binder.BindHttp(req, &form); err != nil {
+		t.Fatal(err)
+	}
+	if form.Name != "bob" || string(form.Raw) != "name=bob" {
+		t.Fatalf("expected the raw form body, got %+v", form)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if err := binder.NewBinder().WithMaxBodySize(4).BindBody(binder.NewHttpBindableRequest(req), &Event{}); !errors.Is(err, binder.ErrBodyTooLarge) {
+		t.Fatalf("expected ErrBodyTooLarge, got %v", err)
+	}
+}
+
+func TestBindRawBodyLimits(t *testing.T) {
+	type Event struct {
+		ID  int            `json:"id"`
+		Raw binder.RawBody `json:"-"`
+	}
+	body := `{"id":7}`
+	for _, size := range []int64{0, -1, math.MaxInt64} {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		var event Event
+		if err := binder.NewBinder().WithMaxBodySize(size).BindBody(binder.NewHttpBindableRequest(req), &event); err != nil || string(event.Raw) != body {
+			t.Fatalf("expected the raw body with a max body size of %d, got %+v, %v", size, event, err)
+		}
+		if replay, err := io.ReadAll(req.Body); err != nil || string(replay) != body {
+			t.Fatalf("expected the request body to be readable again, got %q, %v", replay, err)
+		}
+	}
+}
+
+func TestBindUnsupportedFieldType(t *testing.T) {
+	type Signal struct {
+		Phase   complex128   `query:"phase"`
+		Samples []complex128 `query:"samples"`
+	}
+	req := httptest.NewRequest(http.MethodGet, "/?phase=1%2B2i", nil)
+	err := binder.BindHttp(req, &Signal{})
+	var typeErr *binder.UnsupportedFieldTypeError
+	if !errors.As(err, &typeErr) || typeErr.Field != "phase" || typeErr.Type != reflect.TypeOf(complex128(0)) || !errors.Is(err, binder.ErrUnsupportedFieldType) {
+		t.Fatalf("expected an UnsupportedFieldTypeError for phase, got %v", err)
+	}
+
+	b := binder.NewBinder()
+	b.RegisterTypeConverter(reflect.TypeOf(complex128(0)), func(values []string, dst reflect.Value, field reflect.StructField) error {
+		c, err := strconv.ParseComplex(values[0], 128)
+		if err != nil {
+			return err
+		}
+		dst.SetComplex(c)
+		return nil
+	})
+	req = httptest.NewRequest(http.MethodGet, "/?phase=1%2B2i&samples[0]=1i&samples[1]=2", nil)
+	var signal Signal
+	if err := b.Bind(binder.NewHttpBindableRequest(req), &signal); err != nil {
+		t.Fatal(err)
+	}
+	if signal.Phase != complex(1, 2) || !reflect.DeepEqual(signal.Samples, []complex128{1i, 2}) {
+		t.Fatalf("expected the registered converter to set the complex values, got %+v", signal)
+	}
+}
+
+func TestBindHTTPHeaderField(t *testing.T) {
+	type Proxy struct {
+		http.Header `header:"*"`
+		Trace       http.Header `header:"X-Trace-*"`
+		Agent       string      `header:"User-Agent"`
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("User-Agent", "curl")
+	req.Header.Add("Accept", "text/html")
+	req.Header.Add("Accept", "application/json")
+	req.Header["x-trace-span-id"] = []string{"42"}
+
+	var data Proxy
+	if err := binder.BindHttpHeaders(req, &data); err != nil {
+		t.Fatal(err)
+	}
+	if data.Agent != "curl" || data.Get("User-Agent") != "curl" || !reflect.DeepEqual(data.Values("Accept"), []string{"text/html", "application/json"}) {
+		t.Fatalf("expected the embedded http.Header to receive every header, got %+v", data)
+	}
+	if data.Trace.Get("Span-Id") != "42" {
+		t.Fatalf("expected the captured header keys to be canonicalized, got %+v", data.Trace)
+	}
+}
+
+func TestBindBodyProcessors(t *testing.T) {
+	type Event struct {
+		ID    int                        `json:"id"`
+		Name  string                     `form:"name"`
+		Extra map[string]json.RawMessage `json:"-" bind:",unknown"`
+	}
+	var order []string
+	b := binder.NewBinder()
+	b.UseBodyProcessor("", func(r binder.BindableRequest, body io.Reader) (io.Reader, error) {
+		order = append(order, "all")
+		if r.GetHeaders()["Content-Encoding"] == nil {
+			return body, nil
+		}
+		return gzip.NewReader(body)
+	})
+	b.UseBodyProcessor("application/x-www-form-urlencoded", func(r binder.BindableRequest, body io.Reader) (io.Reader, error) {
+		order = append(order, "form")
+		return base64.NewDecoder(base64.StdEncoding, body), nil
+	})
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	zw.Write([]byte(`{"id":3,"extra":true}`))
+	zw.Close()
+	req := httptest.NewRequest(http.MethodPost, "/", &compressed)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	var event Event
+	if err := httpBinder.BindBody(req, &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.ID != 3 || string(event.Extra["extra"]) != "true" || !reflect.DeepEqual(order, []string{"all"}) {
+		t.Fatalf("expected the decompressed body to be decoded, got %+v after %v", event, order)
+	}
+
+	order = nil
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(base64.StdEncoding.EncodeToString([]byte("name=bob"))))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	event = Event{}
+	if err := httpBinder.BindBody(req, &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.Name != "bob" || !reflect.DeepEqual(order, []string{"all", "form"}) {
+		t.Fatalf("expected the processors of every media type to run first, got %+v after %v", event, order)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"id":3}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	if err := httpBinder.BindBody(req, &Event{}); err == nil {
+		t.Fatalf("expected the error of the processor")
+	}
+}
+
+func TestBindBodyProcessorLimits(t *testing.T) {
+	type Event struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	b := binder.NewBinder()
+	b.UseBodyProcessor("", func(r binder.BindableRequest, body io.Reader) (io.Reader, error) {
+		if r.GetHeaders()["Content-Encoding"] == nil {
+			// a wrapper hiding the body from the binder
+			return struct{ io.Reader }{body}, nil
+		}
+		return gzip.NewReader(body)
+	})
+
+	// a small compressed body inflating past the max body size
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	zw.Write([]byte(`{"id":3,"name":"` + strings.Repeat("a", 4096) + `"}`))
+	zw.Close()
+	req := httptest.NewRequest(http.MethodPost, "/", &compressed)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	var sizeErr *binder.BodySizeError
+	if err := b.WithMaxBodySize(256).BindBody(binder.NewHttpBindableRequest(req), &Event{}); !errors.As(err, &sizeErr) {
+		t.Fatalf("expected the processed body to be capped, got %v", err)
+	}
+
+	// a body going past its Content-Length after a complete value
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"id":3} {"id":4}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.ContentLength = 8
+	var lengthErr *binder.BodyLengthError
+	if err := b.BindBody(binder.NewHttpBindableRequest(req), &Event{}); !errors.As(err, &lengthErr) || lengthErr.Truncated {
+		t.Fatalf("expected the processed body to be checked against its Content-Length, got %v", err)
+	}
+}
+
+func TestBindAuth(t *testing.T) {
+	type Credentials struct {
+		User     string `auth:"basic_user"`
+		Password string `auth:"basic_pass"`
+		Token    string `auth:"bearer"`
+		Page     int    `query:"page"`
+	}
+	req := httptest.NewRequest(http.MethodGet, "/?page=2", nil)
+	req.SetBasicAuth("alice", "s3cret:with:colons")
+	var data Credentials
+	if err := binder.BindHttp(req, &data); err != nil {
+		t.Fatal(err)
+	}
+	if data != (Credentials{User: "alice", Password: "s3cret:with:colons", Page: 2}) {
+		t.Fatalf("expected the basic credentials, got %+v", data)
+	}
+
+	req.Header.Set("Authorization", "bearer abc.def")
+	data = Credentials{}
+	if err := binder.GetHttpBinder().BindAuth(req, &data); err != nil || data != (Credentials{Token: "abc.def"}) {
+		t.Fatalf("expected the bearer token, got %+v, %v", data, err)
+	}
+
+	req.Header.Set("Authorization", "Basic not-base64")
+	data = Credentials{}
+	if err := binder.BindHttp(req, &data); err != nil || data != (Credentials{Page: 2}) {
+		t.Fatalf("expected malformed credentials to be ignored, got %+v, %v", data, err)
+	}
+}
+
+func TestBindHttpContextBinder(t *testing.T) {
+	type Search struct {
+		Tags []string `query:"tags"`
+	}
+	newRequest := func(ctx context.Context) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/?tags[0]=a&tags[1]=b&tags[2]=c&other=1", nil)
+		return req.WithContext(ctx)
+	}
+
+	var data Search
+	if err := binder.BindHttp(newRequest(context.Background()), &data); err != nil || len(data.Tags) != 3 {
+		t.Fatalf("expected the default binder, got %+v, %v", data, err)
+	}
+
+	ctx := binder.ContextWithBindOptions(context.Background(), binder.WithMaxArraySize(1))
+	if err := binder.BindHttp(newRequest(ctx), &Search{}); err == nil {
+		t.Fatalf("expected the options of the context to limit the array size")
+	}
+	ctx = binder.ContextWithBindOptions(ctx, binder.WithMaxArraySize(5), binder.WithStrict(true))
+	if err := binder.BindHttpQueryParams(newRequest(ctx), &Search{}); err == nil || !strings.Contains(err.Error(), "other") {
+		t.Fatalf("expected the options to be applied after the parent ones, got %v", err)
+	}
+
+	strict := binder.NewBinder()
+	strict.StrictKeys = true
+	ctx = binder.ContextWithBinder(context.Background(), strict)
+	if b, err := binder.BinderFromContext(ctx); err != nil || b != strict {
+		t.Fatalf("expected the binder of the context, got %v, %v", b, err)
+	}
+	if err := binder.BindHttp(newRequest(ctx), &Search{}); err == nil {
+		t.Fatalf("expected the binder of the context to reject the unknown key")
+	}
+	if strict.MaxArraySize != binder.MaxArraySize {
+		t.Fatalf("expected the binder of the context to be left untouched")
+	}
+	ctx = binder.ContextWithBindOptions(ctx, binder.Only("unknown"))
+	if err := binder.BindHttp(newRequest(ctx), &Search{}); !errors.Is(err, binder.ErrUnknownSource) {
+		t.Fatalf("expected the error of the options, got %v", err)
+	}
+}
+
+// nilMultipartRequest returns a nil form without error, like requests without any part may do.
+type nilMultipartRequest struct {
+	binder.HttpBindableRequest
+}
+
+func (r nilMultipartRequest) GetMultipartForm(maxBodySize int64) (*multipart.Form, error) {
+	return nil, nil
+}
+
+func TestBindMultipartErrors(t *testing.T) {
+	type Upload struct {
+		Name string `form:"name"`
+	}
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("name", "report")
+	mw.Close()
+	newRequest := func(contentType string, payload string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload))
+		req.Header.Set("Content-Type", contentType)
+		return req
+	}
+
+	var data Upload
+	if err := binder.BindHttp(newRequest(mw.FormDataContentType(), body.String()), &data); err != nil || data.Name != "report" {
+		t.Fatalf("expected the form to be bound on the first parse, got %+v, %v", data, err)
+	}
+
+	for name, req := range map[string]*http.Request{
+		"missing boundary": newRequest("multipart/form-data", body.String()),
+		"wrong boundary":   newRequest("multipart/form-data; boundary=other", body.String()),
+	} {
+		if err := binder.BindHttp(req, &Upload{}); !errors.Is(err, binder.ErrMalformedMultipart) {
+			t.Fatalf("%s: expected ErrMalformedMultipart, got %v", name, err)
+		}
+	}
+
+	req := newRequest(mw.FormDataContentType(), body.String())
+	req.Body = http.MaxBytesReader(httptest.NewRecorder(), req.Body, 10)
+	if err := binder.BindHttp(req, &Upload{}); !errors.Is(err, binder.ErrBodyTooLarge) {
+		t.Fatalf("expected ErrBodyTooLarge, got %v", err)
+	}
+
+	req = newRequest(mw.FormDataContentType(), body.String()[:body.Len()/2])
+	req.ContentLength = int64(body.Len())
+	var lengthErr *binder.BodyLengthError
+	if err := binder.BindHttp(req, &Upload{}); !errors.As(err, &lengthErr) || !lengthErr.Truncated {
+		t.Fatalf("expected a truncated body error, got %v", err)
+	}
+
+	req = newRequest(mw.FormDataContentType(), body.String())
+	if err := binder.NewBinder().BindBody(nilMultipartRequest{binder.NewHttpBindableRequest(req)}, &Upload{}); err != nil {
+		t.Fatalf("expected a nil form to bind nothing, got %v", err)
+	}
+}
+
+func TestBindClaims(t *testing.T) {
+	type Principal struct {
+		Subject  string    `claims:"sub" query:"sub"`
+		TenantID int64     `claims:"tenant_id"`
+		Scopes   []string  `claims:"scopes"`
+		Roles    []string  `claims:"realm_access.roles"`
+		Admin    bool      `claims:"admin"`
+		Expires  time.Time `claims:"exp" convert:"unix"`
+		Page     int       `query:"page"`
+	}
+	var claims map[string]interface{}
+	if err := json.Unmarshal([]byte(`{"sub":"u-1","tenant_id":1234567890,"scopes":["read","write"],
+		"realm_access":{"roles":["admin"]},"admin":true,"exp":1700000000,"nbf":null}`), &claims); err != nil {
+		t.Fatal(err)
+	}
+	b := binder.NewBinder()
+	b.RegisterConverter("unix", func(values []string, dst reflect.Value, field reflect.StructField) error {
+		seconds, err := strconv.ParseInt(values[0], 10, 64)
+		if err != nil {
+			return err
+		}
+		dst.Set(reflect.ValueOf(time.Unix(seconds, 0).UTC()))
+		return nil
+	})
+	b.Claims = binder.ClaimsProviderFunc(func(r binder.BindableRequest) (map[string]interface{}, error) {
+		if r.GetHeaders()["Authorization"] == nil {
+			return nil, errors.New("missing token")
+		}
+		return claims, nil
+	})
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	req := httptest.NewRequest(http.MethodGet, "/?sub=spoofed&page=2", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	var data Principal
+	if err := httpBinder.Bind(req, &data); err != nil {
+		t.Fatal(err)
+	}
+	expected := Principal{Subject: "u-1", TenantID: 1234567890, Scopes: []string{"read", "write"}, Roles: []string{"admin"},
+		Admin: true, Expires: time.Unix(1700000000, 0).UTC(), Page: 2}
+	if !reflect.DeepEqual(data, expected) {
+		t.Fatalf("expected the claims to be bound over the query, got %+v", data)
+	}
+
+	for _, policy := range []binder.MergePolicy{binder.MergeFirstWins, binder.MergeSkipIfNonZero, binder.MergeErrorOnConflict} {
+		b.MergePolicy = policy
+		data = Principal{}
+		if err := httpBinder.Bind(req, &data); err != nil || data.Subject != "u-1" {
+			t.Fatalf("expected the claims to be bound over the query under policy %d, got %+v (err %v)", policy, data, err)
+		}
+	}
+	b.MergePolicy = binder.MergeLastWins
+
+	req.Header.Del("Authorization")
+	if err := httpBinder.BindClaims(req, &Principal{}); err == nil || err.Error() != "missing token" {
+		t.Fatalf("expected the error of the provider, got %v", err)
+	}
+}
+
+func TestBindContextValues(t *testing.T) {
+	type User struct {
+		Name string
+	}
+	type userKey struct{}
+	type requestIDKey struct{}
+	type Request struct {
+		RequestID string `ctx:"request_id" query:"request_id"`
+		User      *User  `ctx:"user"`
+		TenantID  int    `ctx:"tenant_id"`
+		Internal  string `ctx:"internal" bind:"-"`
+		Page      int    `query:"page"`
+	}
+	b := binder.NewBinder()
+	b.ContextValues = func(ctx context.Context) map[string]interface{} {
+		return map[string]interface{}{
+			"request_id": ctx.Value(requestIDKey{}),
+			"user":       ctx.Value(userKey{}),
+			"tenant_id":  "42",
+			"internal":   "secret",
+		}
+	}
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	user := &User{Name: "alice"}
+	ctx := context.WithValue(context.WithValue(context.Background(), userKey{}, user), requestIDKey{}, "req-1")
+	req := httptest.NewRequest(http.MethodGet, "/?request_id=spoofed&page=3", nil).WithContext(ctx)
+	var data Request
+	if err := httpBinder.Bind(req, &data); err != nil {
+		t.Fatal(err)
+	}
+	if data.RequestID != "req-1" || data.User != user || data.TenantID != 42 || data.Internal != "" || data.Page != 3 {
+		t.Fatalf("expected the context values to be bound over the query, got %+v", data)
+	}
+
+	data = Request{}
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	if err := httpBinder.BindContextValues(req, &data); err != nil || data.User != nil || data.RequestID != "" || data.TenantID != 42 {
+		t.Fatalf("expected the missing context values to be skipped, got %+v, %v", data, err)
+	}
+}
+
+func TestBindSession(t *testing.T) {
+	type Cart struct {
+		Items []string
+	}
+	type Checkout struct {
+		CartID  int      `session:"cart_id"`
+		Cart    *Cart    `session:"cart"`
+		Flashes []string `session:"flashes"`
+		Coupon  string   `query:"coupon"`
+	}
+	cart := &Cart{Items: []string{"book"}}
+	b := binder.NewBinder()
+	httpBinder := &binder.HttpBinder{Binder: b}
+	req := httptest.NewRequest(http.MethodGet, "/?coupon=SALE", nil)
+
+	var data Checkout
+	if err := httpBinder.Bind(req, &data); err != nil || !reflect.DeepEqual(data, Checkout{Coupon: "SALE"}) {
+		t.Fatalf("expected nothing to be bound without a provider, got %+v, %v", data, err)
+	}
+
+	b.Session = binder.SessionProviderFunc(func(r binder.BindableRequest) (map[string]interface{}, error) {
+		if r.GetHeaders()["Cookie"] == nil {
+			return nil, errors.New("no session")
+		}
+		return map[string]interface{}{"cart_id": 7, "cart": cart, "flashes": []interface{}{"saved", "welcome"}}, nil
+	})
+	req.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+	data = Checkout{}
+	if err := httpBinder.Bind(req, &data); err != nil {
+		t.Fatal(err)
+	}
+	if data.CartID != 7 || data.Cart != cart || !reflect.DeepEqual(data.Flashes, []string{"saved", "welcome"}) || data.Coupon != "SALE" {
+		t.Fatalf("expected the session values, got %+v", data)
+	}
+
+	if err := httpBinder.BindSession(httptest.NewRequest(http.MethodGet, "/", nil), &Checkout{}); err == nil {
+		t.Fatalf("expected the error of the provider")
+	}
+}
+
+func TestBindEnv(t *testing.T) {
+	type Flags struct {
+		Search  bool   `query:"search" env:"FEATURE_SEARCH"`
+		Region  string `env:"REGION"`
+		Timeout int    `query:"timeout" env:"TIMEOUT"`
+	}
+	env := map[string]string{"FEATURE_SEARCH": "true", "REGION": "eu-west-1", "TIMEOUT": "30"}
+	b := binder.NewBinder()
+	b.Env = func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	var data Flags
+	if err := httpBinder.Bind(httptest.NewRequest(http.MethodGet, "/", nil), &data); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(data, Flags{Search: true, Region: "eu-west-1", Timeout: 30}) {
+		t.Fatalf("expected the env values, got %+v", data)
+	}
+
+	for _, policy := range []binder.MergePolicy{binder.MergeLastWins, binder.MergeFirstWins, binder.MergeSkipIfNonZero, binder.MergeErrorOnConflict} {
+		b.MergePolicy = policy
+		data = Flags{}
+		if err := httpBinder.Bind(httptest.NewRequest(http.MethodGet, "/?search=false&timeout=5", nil), &data); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(data, Flags{Search: false, Region: "eu-west-1", Timeout: 5}) {
+			t.Fatalf("expected the request values to override the env values with policy %d, got %+v", policy, data)
+		}
+	}
+
+	env["TIMEOUT"] = "soon"
+	if err := httpBinder.BindEnv(httptest.NewRequest(http.MethodGet, "/", nil), &Flags{}); err == nil {
+		t.Fatalf("expected the conversion error of the env value")
+	}
+}
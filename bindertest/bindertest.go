@@ -0,0 +1,460 @@
+// Package bindertest generates table-driven tests locking in how a destination type is bound: for each
+// tagged field and each source it builds requests with representative inputs, in every notation the source
+// accepts, and the outcome they are expected to have.
+//
+//	func TestCreateUserBinding(t *testing.T) {
+//		bindertest.Run(t, binder.NewBinder(), &CreateUser{})
+//	}
+//
+// Only the fields of string, bool, integer and float kinds, pointers and slices of them, and the same fields
+// of nested structs, are covered. Fields with a converter, a `maxbytes` limit, tag options, or a type with its
+// own unmarshaling are skipped, their cases being left to hand-written tests.
+package bindertest
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gobigbang/binder"
+)
+
+// Notations of the generated inputs.
+const (
+	NotationPlain    = "plain"    // single value, i.e. `?age=42`
+	NotationRepeated = "repeated" // repeated key, i.e. `?tags=a&tags=b`
+	NotationIndexed  = "indexed"  // indexed keys, i.e. `?tags[0]=a&tags[1]=b`
+	NotationAppend   = "append"   // PHP-style append keys, i.e. `?tags[]=a&tags[]=b`
+	NotationBracket  = "bracket"  // deep object key, i.e. `?address[city]=x`
+	NotationDot      = "dot"      // dotted key, i.e. `?address.city=x`
+	NotationJSON     = "json"     // JSON document, nested objects for nested structs
+)
+
+// Case is a generated input of a field and its expected outcome.
+type Case struct {
+	Name     string               // unique name of the case, i.e. `query/age/overflow`
+	Source   string               // source of the input, a binder Source* name, or `form` and `json` for the bodies
+	Field    string               // path of the struct field, i.e. `Address.City`
+	Notation string               // notation of the input, a Notation* constant
+	Request  func() *http.Request // builds a new request holding the input
+	WantErr  bool                 // the bind is expected to fail
+	Want     interface{}          // value of the field expected after a successful bind
+
+	typ   reflect.Type
+	index []int
+}
+
+// Check binds a new destination from the request of the case with the method of its source, and reports
+// how the outcome differs from the expected one.
+func (c Case) Check(b binder.Binder) error {
+	dst := reflect.New(c.typ)
+	r := binder.NewHttpBindableRequest(c.Request())
+	var err error
+	switch c.Source {
+	case binder.SourceQuery:
+		err = b.BindQueryParams(r, dst.Interface())
+	case binder.SourcePath:
+		err = b.BindPathParams(r, dst.Interface())
+	case binder.SourceHeader:
+		err = b.BindHeaders(r, dst.Interface())
+	default:
+		err = b.BindBody(r, dst.Interface())
+	}
+	if c.WantErr {
+		if err == nil {
+			return fmt.Errorf("%s: expected an error, got %#v", c.Field, dst.Elem().FieldByIndex(c.index).Interface())
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s: unexpected error: %w", c.Field, err)
+	}
+	if got := dst.Elem().FieldByIndex(c.index).Interface(); !reflect.DeepEqual(got, c.Want) {
+		return fmt.Errorf("%s: expected %#v, got %#v", c.Field, c.Want, got)
+	}
+	return nil
+}
+
+// Run generates the cases of the destination and checks each of them in a subtest.
+func Run(t *testing.T, b binder.Binder, dst interface{}) {
+	t.Helper()
+	for _, c := range Generate(b, dst) {
+		t.Run(c.Name, func(t *testing.T) {
+			if err := c.Check(b); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// Generate returns the cases of the destination, a pointer to a struct, in the order of its fields and then
+// of the sources.
+// The tag names and the type converters of a *binder.DefaultBinder are taken into account to read the keys
+// of its sources and to skip the types it converts; the default tag names are used for other binders.
+func Generate(b binder.Binder, dst interface{}) []Case {
+	typ := reflect.TypeOf(dst)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil
+	}
+	g := &generator{
+		root:         typ,
+		bindTag:      binder.DefaultBindTagName,
+		converterTag: binder.DefaultConverterTagName,
+		sources: []source{
+			{binder.SourcePath, binder.DefaultParamTagName},
+			{binder.SourceQuery, binder.DefaultQueryTagName},
+			{binder.SourceHeader, binder.DefaultHeaderTagName},
+			{"form", binder.DefaultFormTagName},
+			{"json", "json"},
+		},
+	}
+	if db, ok := b.(*binder.DefaultBinder); ok {
+		g.converters = db.TypeConverters
+		g.bindTag = db.BindTagName
+		g.converterTag = db.ConverterTagName
+		g.sources = []source{
+			{binder.SourcePath, db.ParamTagName},
+			{binder.SourceQuery, db.QueryTagName},
+			{binder.SourceHeader, db.HeaderTagName},
+			{"form", db.FormTagName},
+			{"json", "json"},
+		}
+	}
+	g.walk(typ, nil, "", parentKeys{})
+	return g.cases
+}
+
+// parentKeys holds the keys of the struct field holding the fields being walked, per source tag.
+type parentKeys map[string]string
+
+// source is a source of the generated cases, with the tag naming its keys.
+type source struct {
+	name, tag string
+}
+
+type generator struct {
+	root         reflect.Type
+	converters   map[reflect.Type]binder.ConverterFunc
+	bindTag      string
+	converterTag string
+	sources      []source // in the order of the cases
+	cases        []Case
+}
+
+func (g *generator) walk(typ reflect.Type, index []int, path string, parents parentKeys) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+		fieldPath := path + field.Name
+		if !field.IsExported() || field.Tag.Get(g.bindTag) == "-" || field.Tag.Get(g.converterTag) != "" || field.Tag.Get("maxbytes") != "" {
+			continue
+		}
+		if field.Type.Kind() == reflect.Struct && !g.convertsItself(field.Type) {
+			if field.Anonymous || len(parents) > 0 {
+				// embedded structs share the keys of their parent, and deeper levels are not generated
+				if field.Anonymous && len(parents) == 0 {
+					g.walk(field.Type, fieldIndex, path, parents)
+				}
+				continue
+			}
+			keys := parentKeys{}
+			for _, source := range g.sources {
+				if name, ok := plainTagName(field, source.tag); ok {
+					keys[source.tag] = name
+				}
+			}
+			if len(keys) > 0 {
+				g.walk(field.Type, fieldIndex, fieldPath+".", keys)
+			}
+			continue
+		}
+		kind, ok := g.valueKind(field.Type)
+		if !ok {
+			continue
+		}
+		for _, source := range g.sources {
+			name, ok := plainTagName(field, source.tag)
+			if !ok {
+				continue
+			}
+			parent, nested := parents[source.tag]
+			if len(parents) > 0 && !nested {
+				continue
+			}
+			g.addCases(source.name, source.tag, field.Type, kind, fieldIndex, fieldPath, parent, name)
+		}
+	}
+}
+
+// plainTagName returns the name of the field in the source tag, reporting false without a name or with
+// options changing its binding. The omitempty option of the json tag is accepted.
+func plainTagName(field reflect.StructField, tag string) (string, bool) {
+	value, ok := field.Tag.Lookup(tag)
+	if !ok {
+		return "", false
+	}
+	name, options, _ := strings.Cut(value, ",")
+	if name == "" || name == "-" || strings.HasSuffix(name, "*") || (options != "" && !(tag == "json" && options == "omitempty")) {
+		return "", false
+	}
+	return name, true
+}
+
+var (
+	textUnmarshalerType     = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonUnmarshalerType     = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	bindUnmarshalerType     = reflect.TypeOf((*binder.BindUnmarshaler)(nil)).Elem()
+	bindEnumType            = reflect.TypeOf((*binder.BindEnum)(nil)).Elem()
+	scannerType             = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	convertsItselfInterface = []reflect.Type{textUnmarshalerType, jsonUnmarshalerType, bindUnmarshalerType, bindEnumType, scannerType}
+)
+
+// convertsItself reports whether values of the type are converted by their own methods or a type converter.
+func (g *generator) convertsItself(typ reflect.Type) bool {
+	if _, ok := g.converters[typ]; ok {
+		return true
+	}
+	for _, iface := range convertsItselfInterface {
+		if typ.Implements(iface) || reflect.PointerTo(typ).Implements(iface) {
+			return true
+		}
+	}
+	return false
+}
+
+// valueKind returns the kind of the scalar values of a field type: the type itself, a pointer to it or a slice of it.
+func (g *generator) valueKind(typ reflect.Type) (reflect.Kind, bool) {
+	if typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice {
+		if g.convertsItself(typ) {
+			return 0, false
+		}
+		typ = typ.Elem()
+	}
+	if g.convertsItself(typ) {
+		return 0, false
+	}
+	switch kind := typ.Kind(); kind {
+	case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return kind, true
+	}
+	return 0, false
+}
+
+// sample is a representative input of a kind, valid when want is set.
+type sample struct {
+	name string
+	raw  string
+	json string // JSON literal of the input
+	want interface{}
+}
+
+// samples returns the representative inputs of the scalar kind of the given bit size: typical and boundary
+// values, and the inputs failing the conversion.
+func samples(kind reflect.Kind, bits int) []sample {
+	switch kind {
+	case reflect.String:
+		return []sample{
+			{name: "value", raw: "hello", json: `"hello"`, want: "hello"},
+			{name: "unicode", raw: "héllo ✓", json: `"héllo ✓"`, want: "héllo ✓"},
+		}
+	case reflect.Bool:
+		return []sample{
+			{name: "true", raw: "true", json: "true", want: true},
+			{name: "false", raw: "false", json: "false", want: false},
+			{name: "invalid", raw: "maybe", json: `"maybe"`},
+		}
+	case reflect.Float32, reflect.Float64:
+		return []sample{
+			{name: "value", raw: "1.5", json: "1.5", want: 1.5},
+			{name: "negative", raw: "-2.25", json: "-2.25", want: -2.25},
+			{name: "invalid", raw: "abc", json: `"abc"`},
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		max := int64(math.MaxInt64 >> (64 - bits))
+		min := -max - 1
+		overflow := strconv.FormatUint(uint64(max)+1, 10)
+		return []sample{
+			{name: "value", raw: "42", json: "42", want: int64(42)},
+			{name: "negative", raw: "-7", json: "-7", want: int64(-7)},
+			{name: "max", raw: strconv.FormatInt(max, 10), json: strconv.FormatInt(max, 10), want: max},
+			{name: "min", raw: strconv.FormatInt(min, 10), json: strconv.FormatInt(min, 10), want: min},
+			{name: "overflow", raw: overflow, json: overflow},
+			{name: "invalid", raw: "abc", json: `"abc"`},
+		}
+	default:
+		max := uint64(math.MaxUint64 >> (64 - bits))
+		overflow := new(big.Int).Add(new(big.Int).SetUint64(max), big.NewInt(1)).String()
+		return []sample{
+			{name: "value", raw: "42", json: "42", want: uint64(42)},
+			{name: "max", raw: strconv.FormatUint(max, 10), json: strconv.FormatUint(max, 10), want: max},
+			{name: "overflow", raw: overflow, json: overflow},
+			{name: "negative", raw: "-1", json: "-1"},
+			{name: "invalid", raw: "abc", json: `"abc"`},
+		}
+	}
+}
+
+// addCases adds the cases of a field for a source, the key of the parent struct prefixing its name when set.
+func (g *generator) addCases(source string, tag string, typ reflect.Type, kind reflect.Kind, index []int, path string, parent string, name string) {
+	scalar := typ
+	if typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice {
+		scalar = typ.Elem()
+	}
+	bits := strconv.IntSize
+	if kind != reflect.String && kind != reflect.Bool && kind != reflect.Int && kind != reflect.Uint {
+		bits = scalar.Bits()
+	}
+	all := samples(kind, bits)
+	var valid []sample
+	var invalid *sample
+	for i, s := range all {
+		if s.want != nil {
+			valid = append(valid, s)
+		} else if invalid == nil {
+			invalid = &all[i]
+		}
+	}
+
+	add := func(notation string, sampleName string, build func() *http.Request, want ...interface{}) {
+		c := Case{
+			Name:     source + "/" + path + "/" + sampleName,
+			Source:   source,
+			Field:    path,
+			Notation: notation,
+			Request:  build,
+			WantErr:  len(want) == 0,
+			typ:      g.root,
+			index:    index,
+		}
+		if notation != NotationPlain && notation != NotationJSON {
+			c.Name = source + "/" + path + "/" + notation + "/" + sampleName
+		}
+		if !c.WantErr {
+			c.Want = wantValue(typ, scalar, want)
+		}
+		g.cases = append(g.cases, c)
+	}
+
+	if source == "json" {
+		document := func(literal string) func() *http.Request {
+			body := fmt.Sprintf("{%q:%s}", name, literal)
+			if parent != "" {
+				body = fmt.Sprintf("{%q:%s}", parent, body)
+			}
+			return func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+				req.Header.Set("Content-Type", binder.MIMEApplicationJSON)
+				return req
+			}
+		}
+		if typ.Kind() != reflect.Slice {
+			for _, s := range all {
+				add(NotationJSON, s.name, document(s.json), wants(s)...)
+			}
+			return
+		}
+		add(NotationJSON, "array", document("["+valid[0].json+","+valid[1].json+"]"), valid[0].want, valid[1].want)
+		if invalid != nil {
+			add(NotationJSON, invalid.name, document("["+valid[0].json+","+invalid.json+"]"))
+		}
+		return
+	}
+
+	keys := map[string]string{NotationPlain: name}
+	if parent != "" {
+		keys = map[string]string{NotationBracket: parent + "[" + name + "]", NotationDot: parent + "." + name}
+	}
+	for _, notation := range []string{NotationPlain, NotationBracket, NotationDot} {
+		key, ok := keys[notation]
+		if !ok {
+			continue
+		}
+		if typ.Kind() != reflect.Slice {
+			for _, s := range all {
+				add(notation, s.name, request(source, url.Values{key: {s.raw}}), wants(s)...)
+			}
+			continue
+		}
+		if source == binder.SourcePath || parent != "" {
+			continue
+		}
+		add(NotationRepeated, "values", request(source, url.Values{key: {valid[0].raw, valid[1].raw}}), valid[0].want, valid[1].want)
+		if invalid != nil {
+			add(NotationRepeated, invalid.name, request(source, url.Values{key: {valid[0].raw, invalid.raw}}))
+		}
+		if source == binder.SourceHeader {
+			continue
+		}
+		add(NotationIndexed, "values", request(source, url.Values{key + "[0]": {valid[0].raw}, key + "[1]": {valid[1].raw}}), valid[0].want, valid[1].want)
+		add(NotationAppend, "values", request(source, url.Values{key + "[]": {valid[0].raw, valid[1].raw}}), valid[0].want, valid[1].want)
+	}
+}
+
+// wants returns the expected value of a valid sample, none for an invalid one.
+func wants(s sample) []interface{} {
+	if s.want == nil {
+		return nil
+	}
+	return []interface{}{s.want}
+}
+
+// wantValue converts the expected scalar values into the type of the field.
+func wantValue(typ reflect.Type, scalar reflect.Type, values []interface{}) interface{} {
+	switch typ.Kind() {
+	case reflect.Ptr:
+		ptr := reflect.New(scalar)
+		ptr.Elem().Set(reflect.ValueOf(values[0]).Convert(scalar))
+		return ptr.Interface()
+	case reflect.Slice:
+		slice := reflect.MakeSlice(typ, len(values), len(values))
+		for i, value := range values {
+			slice.Index(i).Set(reflect.ValueOf(value).Convert(scalar))
+		}
+		return slice.Interface()
+	}
+	return reflect.ValueOf(values[0]).Convert(typ).Interface()
+}
+
+// request returns the function building a request holding the values in the source.
+func request(source string, values url.Values) func() *http.Request {
+	return func() *http.Request {
+		switch source {
+		case binder.SourcePath:
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for key, v := range values {
+				req.Pattern = "/{" + key + "}"
+				req.SetPathValue(key, v[0])
+			}
+			return req
+		case binder.SourceHeader:
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for key, v := range values {
+				for _, value := range v {
+					req.Header.Add(key, value)
+				}
+			}
+			return req
+		case binder.SourceQuery:
+			return httptest.NewRequest(http.MethodGet, "/?"+values.Encode(), nil)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(values.Encode()))
+		req.Header.Set("Content-Type", binder.MIMEApplicationForm)
+		return req
+	}
+}
@@ -0,0 +1,73 @@
+package bindertest_test
+
+import (
+	"testing"
+
+	"github.com/gobigbang/binder"
+	"github.com/gobigbang/binder/bindertest"
+)
+
+type Address struct {
+	City string `query:"city" form:"city" json:"city"`
+	Zip  uint16 `query:"zip" form:"zip" json:"zip"`
+}
+
+type CreateUser struct {
+	ID      int64     `param:"id"`
+	Name    string    `query:"name" form:"name" json:"name"`
+	Age     int8      `query:"age" form:"age" json:"age,omitempty"`
+	Score   *float64  `query:"score" json:"score"`
+	Admin   bool      `form:"admin" json:"admin"`
+	Tags    []string  `query:"tags" form:"tags" header:"X-Tags" json:"tags"`
+	Ports   []uint    `query:"ports" json:"ports"`
+	Trace   string    `header:"X-Trace-Id"`
+	Address Address   `query:"address" form:"address" json:"address"`
+	Size    int       `query:"size" convert:"bytesize"`
+	Ignored string    `query:"ignored" bind:"-"`
+	Joined  []float32 `query:"joined,split"`
+}
+
+func TestGenerate(t *testing.T) {
+	bindertest.Run(t, binder.NewBinder(), &CreateUser{})
+}
+
+func TestCheckReportsRegressions(t *testing.T) {
+	cases := bindertest.Generate(binder.NewBinder(), &CreateUser{})
+	for _, c := range cases {
+		switch c.Field {
+		case "Size", "Ignored", "Joined":
+			t.Fatalf("expected the fields with a converter, excluded or with tag options to be skipped, got %s", c.Name)
+		}
+	}
+
+	// the cases read the keys of the tags of the binder, and a binder reading other keys no longer binds them
+	renamed := binder.NewBinder()
+	renamed.QueryTagName = "q"
+	renamed.BindTagName = "binding"
+	cases = bindertest.Generate(renamed, &RenamedSearch{})
+	generated, failed := 0, 0
+	for _, c := range cases {
+		if c.Field == "Ignored" {
+			t.Fatalf("expected the field excluded with the bind tag of the binder to be skipped, got %s", c.Name)
+		}
+		if c.Source != binder.SourceQuery {
+			continue
+		}
+		generated++
+		if err := c.Check(renamed); err != nil {
+			t.Fatalf("expected the case to pass with its binder, got %v", err)
+		}
+		if !c.WantErr && c.Check(binder.NewBinder()) != nil {
+			failed++
+		}
+	}
+	if generated == 0 || failed == 0 {
+		t.Fatalf("expected the query cases to pass with their query tag only, got %d cases, %d failed", generated, failed)
+	}
+}
+
+type RenamedSearch struct {
+	Term    string `q:"term" query:"query"`
+	Page    int    `q:"page"`
+	Ignored string `q:"ignored" binding:"-"`
+}
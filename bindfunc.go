@@ -0,0 +1,43 @@
+package binder
+
+import "errors"
+
+// Sequence returns a BindFunc running the functions in order, stopping at the first error,
+// i.e. `Sequence(b.BindHeaders, b.BindBody)`.
+func Sequence(fns ...BindFunc) BindFunc {
+	return func(r BindableRequest, i interface{}) error {
+		for _, fn := range fns {
+			if err := fn(r, i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// FirstSuccess returns a BindFunc running the functions in order until one succeeds, returning all
+// the errors joined when none does. The values bound by a failed function are not reverted.
+func FirstSuccess(fns ...BindFunc) BindFunc {
+	return func(r BindableRequest, i interface{}) error {
+		var errs []error
+		for _, fn := range fns {
+			err := fn(r, i)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, err)
+		}
+		return errors.Join(errs...)
+	}
+}
+
+// When returns a BindFunc running fn only when the predicate holds for the request,
+// i.e. `When(func(r BindableRequest) bool { return r.GetContentLength() > 0 }, b.BindBody)`.
+func When(predicate func(r BindableRequest) bool, fn BindFunc) BindFunc {
+	return func(r BindableRequest, i interface{}) error {
+		if !predicate(r) {
+			return nil
+		}
+		return fn(r, i)
+	}
+}
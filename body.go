@@ -0,0 +1,209 @@
+package binder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+// BodyLengthError is returned by BindBody when the body does not match its Content-Length:
+// the body ends before (a truncated upload) or goes past the announced length.
+type BodyLengthError struct {
+	ContentLength int64 // announced length of the body
+	Truncated     bool  // the body ended before ContentLength bytes
+}
+
+func (e *BodyLengthError) Error() string {
+	if e.Truncated {
+		return fmt.Sprintf("request body is shorter than its Content-Length of %d bytes", e.ContentLength)
+	}
+	return fmt.Sprintf("request body exceeds its Content-Length of %d bytes", e.ContentLength)
+}
+
+// Unwrap returns io.ErrUnexpectedEOF for truncated bodies.
+func (e *BodyLengthError) Unwrap() error {
+	if e.Truncated {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
+// BodySizeError is returned by BindBody when the body exceeds the MaxBodySize of the binder. It matches
+// ErrBodyTooLarge.
+type BodySizeError struct {
+	MaxBodySize int64 // max number of bytes of the body
+}
+
+func (e *BodySizeError) Error() string {
+	return fmt.Sprintf("request body exceeds the max body size of %d bytes", e.MaxBodySize)
+}
+
+// Unwrap returns ErrBodyTooLarge.
+func (e *BodySizeError) Unwrap() error {
+	return ErrBodyTooLarge
+}
+
+// maxBodyReader reports a *BodySizeError once more than max bytes of the body are read, and on any read after.
+type maxBodyReader struct {
+	body      io.ReadCloser
+	max       int64
+	remaining int64
+	err       error
+}
+
+func newMaxBodyReader(body io.ReadCloser, max int64) *maxBodyReader {
+	return &maxBodyReader{body: body, max: max, remaining: max}
+}
+
+func (r *maxBodyReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	if int64(len(p)) > r.remaining+1 {
+		// one more byte tells a body of exactly max bytes from a larger one
+		p = p[:r.remaining+1]
+	}
+	n, err := r.body.Read(p)
+	if int64(n) > r.remaining {
+		n = int(r.remaining)
+		r.remaining = 0
+		r.err = &BodySizeError{MaxBodySize: r.max}
+		return n, r.err
+	}
+	r.remaining -= int64(n)
+	return n, err
+}
+
+func (r *maxBodyReader) Close() error {
+	return r.body.Close()
+}
+
+// limitBody makes the bind fail with a *BodySizeError when the body exceeds MaxBodySize: at once when its
+// Content-Length does, or else once the decoders, or the form parser of an http request, read past it.
+// Bodies of a known length are already checked against it while read. It returns the error of a body
+// already read past the limit, i.e. by the CSRF check. A MaxBodySize of 0 or less means no limit.
+func (b *DefaultBinder) limitBody(r BindableRequest) error {
+	if b.MaxBodySize <= 0 {
+		return nil
+	}
+	length := r.GetContentLength()
+	if length > b.MaxBodySize {
+		return &BodySizeError{MaxBodySize: b.MaxBodySize}
+	}
+	if length >= 0 {
+		return nil
+	}
+	if hr, ok := httpRequest(r); ok {
+		if hr.Body == nil || hr.Body == http.NoBody {
+			return nil
+		}
+		limited, ok := hr.Body.(*maxBodyReader)
+		if !ok {
+			limited = newMaxBodyReader(hr.Body, b.MaxBodySize)
+			hr.Body = limited
+		}
+		return limited.err
+	}
+	if mr, ok := r.(*mediaTypeRequest); ok && mr.body == nil {
+		if body := mr.BindableRequest.GetBody(); body != nil {
+			mr.body = newMaxBodyReader(io.NopCloser(body), b.MaxBodySize)
+		}
+	}
+	return nil
+}
+
+// contentLengthReader reports a *BodyLengthError when the body read does not match its Content-Length.
+type contentLengthReader struct {
+	reader        io.Reader
+	contentLength int64
+	remaining     int64
+}
+
+func (r *contentLengthReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+	switch {
+	case r.remaining < 0:
+		return n, &BodyLengthError{ContentLength: r.contentLength}
+	case err == io.EOF && r.remaining > 0, errors.Is(err, io.ErrUnexpectedEOF):
+		return n, &BodyLengthError{ContentLength: r.contentLength, Truncated: true}
+	}
+	return n, err
+}
+
+// bodyLengthError converts the io.ErrUnexpectedEOF of a truncated body read by the request itself,
+// like ParseForm does, into a *BodyLengthError.
+func bodyLengthError(r BindableRequest, err error) error {
+	var lengthErr *BodyLengthError
+	if err == nil || errors.As(err, &lengthErr) || !errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+	return &BodyLengthError{ContentLength: r.GetContentLength(), Truncated: true}
+}
+
+// bodyLengthError returns a *BodyLengthError when the body read went past its Content-Length, whether
+// read by the decoders or by the body processors.
+func (r *mediaTypeRequest) bodyLengthError() error {
+	if r.length != nil && r.length.remaining < 0 {
+		return &BodyLengthError{ContentLength: r.length.contentLength}
+	}
+	return nil
+}
+
+// multipartError sorts the error of a multipart body parsed by the request: a *BodyLengthError for truncated
+// bodies, a *BodySizeError past MaxBodySize, ErrBodyTooLarge for the bodies exceeding the limits of the parser
+// or of an http.MaxBytesReader, and ErrMalformedMultipart otherwise, the original error being wrapped with the
+// last two.
+func multipartError(r BindableRequest, err error) error {
+	var lengthErr *BodyLengthError
+	if err = bodyLengthError(r, err); err == nil || errors.As(err, &lengthErr) {
+		return err
+	}
+	var sizeErr *BodySizeError
+	if errors.As(err, &sizeErr) {
+		return sizeErr
+	}
+	var maxBytesErr *http.MaxBytesError
+	if errors.Is(err, multipart.ErrMessageTooLarge) || errors.As(err, &maxBytesErr) {
+		return fmt.Errorf("%w: %w", ErrBodyTooLarge, err)
+	}
+	return fmt.Errorf("%w: %w", ErrMalformedMultipart, err)
+}
+
+// checkMultipartLength makes an http request parse its multipart body through the Content-Length check,
+// as the multipart reader quietly returns an empty form when the body ends within the headers of a part.
+// Bodies replaced by a raw body capture or the body processors are left alone.
+func (r *mediaTypeRequest) checkMultipartLength() {
+	hr, ok := httpRequest(r)
+	if !ok || r.GetContentLength() <= 0 {
+		return
+	}
+	if cr, ok := r.GetBody().(*contentLengthReader); ok {
+		hr.Body = io.NopCloser(cr)
+	}
+}
+
+// emptyBody reports whether a body of unknown length, like a chunked one, is empty. It reads the first byte
+// of the body and replays it to the decoders, and to the form parser of an http request.
+func (r *mediaTypeRequest) emptyBody() bool {
+	body := r.GetBody()
+	if body == nil {
+		return true
+	}
+	var first [1]byte
+	n, err := io.ReadFull(body, first[:])
+	if n == 0 && err == io.EOF {
+		return true
+	}
+	r.body = io.MultiReader(bytes.NewReader(first[:n]), body)
+	if hr, ok := httpRequest(r); ok {
+		hr.Body = struct {
+			io.Reader
+			io.Closer
+		}{r.body, hr.Body}
+	}
+	return false
+}
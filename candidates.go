@@ -0,0 +1,52 @@
+package binder
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrNoCandidate is returned by BindCandidates when no candidate matches the request
+var ErrNoCandidate = errors.New("no destination matches the request media type")
+
+// Candidate is a destination that BindCandidates can select for a request.
+type Candidate struct {
+	// MediaType to match against the request Content-Type, empty matches any
+	MediaType string
+	// Version to match against the `version` media type param or the version header, empty matches any
+	Version string
+	// New returns a new destination
+	New func() interface{}
+	// Binder used for the destination, GetBinder() when nil
+	Binder Binder
+}
+
+// BindCandidates binds the request into the first candidate matching its Content-Type and version, useful
+// for endpoints accepting several payload shapes (i.e. v1 and v2). The version is read from the `version`
+// media type param, or from the versionHeader when not empty. It returns the index of the chosen candidate
+// and the bound destination.
+func BindCandidates(r BindableRequest, versionHeader string, candidates ...Candidate) (int, interface{}, error) {
+	mediatype, params := GetMediaType(r)
+	version := params["version"]
+	if version == "" && versionHeader != "" {
+		// header names are case-insensitive, GetHeaders may not hold them in canonical form
+		if _, values, ok := lookupInput(r.GetHeaders(), versionHeader, true); ok && len(values) > 0 {
+			version = values[0]
+		}
+	}
+
+	for i, candidate := range candidates {
+		if candidate.MediaType != "" && !strings.EqualFold(candidate.MediaType, mediatype) {
+			continue
+		}
+		if candidate.Version != "" && candidate.Version != version {
+			continue
+		}
+		binder := candidate.Binder
+		if binder == nil {
+			binder = GetBinder()
+		}
+		destination := candidate.New()
+		return i, destination, binder.Bind(r, destination)
+	}
+	return -1, nil, ErrNoCandidate
+}
@@ -0,0 +1,69 @@
+package binder
+
+import (
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"reflect"
+	"strings"
+)
+
+var httpHeaderType = reflect.TypeOf(http.Header(nil))
+
+// captureKeys binds the keys of the source starting with the prefix into the map field, with the prefix removed,
+// i.e. `header:"X-Meta-*"` captures `X-Meta-Region` under `Region`. An empty prefix captures every key.
+// Prefixes match whatever their case like the other keys of the source, see caseInsensitiveKeys.
+// The map has string keys and string or []string values, the keys of an http.Header being canonicalized
+// so that its Get method finds them.
+func (b *DefaultBinder) captureKeys(field reflect.Value, typeField reflect.StructField, tag string, prefix string, data map[string][]string) error {
+	typ := field.Type()
+	if typ.Kind() != reflect.Map || typ.Key().Kind() != reflect.String ||
+		(typ.Elem().Kind() != reflect.String && (typ.Elem().Kind() != reflect.Slice || typ.Elem().Elem().Kind() != reflect.String)) {
+		return fmt.Errorf("%s: prefix capture requires a map of strings or string slices, got %s", typeField.Name, typ)
+	}
+	caseInsensitive := b.caseInsensitiveKeys(tag)
+	for key, values := range data {
+		if len(values) == 0 || !hasKeyPrefix(key, prefix, caseInsensitive) {
+			continue
+		}
+		if field.IsNil() {
+			field.Set(reflect.MakeMap(typ))
+		}
+		name := key[len(prefix):]
+		if typ == httpHeaderType {
+			name = textproto.CanonicalMIMEHeaderKey(name)
+		}
+		mapKey := reflect.ValueOf(name).Convert(typ.Key())
+		if typ.Elem().Kind() == reflect.String {
+			field.SetMapIndex(mapKey, reflect.ValueOf(values[0]).Convert(typ.Elem()))
+			continue
+		}
+		elem := reflect.MakeSlice(typ.Elem(), len(values), len(values))
+		for i, value := range values {
+			elem.Index(i).SetString(value)
+		}
+		field.SetMapIndex(mapKey, elem)
+	}
+	return nil
+}
+
+// hasKeyPrefix reports whether the key starts with the prefix, ignoring case if asked.
+func hasKeyPrefix(key string, prefix string, caseInsensitive bool) bool {
+	if len(key) < len(prefix) {
+		return false
+	}
+	return key[:len(prefix)] == prefix || (caseInsensitive && strings.EqualFold(key[:len(prefix)], prefix))
+}
+
+// equalKeys reports whether the keys are equal, ignoring case if asked.
+func equalKeys(key string, name string, caseInsensitive bool) bool {
+	return key == name || (caseInsensitive && strings.EqualFold(key, name))
+}
+
+// cutKeyPrefix returns the key without the prefix, ignoring case if asked, and whether the key had the prefix.
+func cutKeyPrefix(key string, prefix string, caseInsensitive bool) (string, bool) {
+	if !hasKeyPrefix(key, prefix, caseInsensitive) {
+		return key, false
+	}
+	return key[len(prefix):], true
+}
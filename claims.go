@@ -0,0 +1,93 @@
+package binder
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// ClaimsProvider returns the verified claims of a request, i.e. of its JWT, bound with the claims tag.
+// The provider is responsible for verifying the token; an error fails the bind.
+type ClaimsProvider interface {
+	Claims(r BindableRequest) (map[string]interface{}, error)
+}
+
+// ClaimsProviderFunc adapts a function to the ClaimsProvider interface.
+type ClaimsProviderFunc func(r BindableRequest) (map[string]interface{}, error)
+
+func (f ClaimsProviderFunc) Claims(r BindableRequest) (map[string]interface{}, error) {
+	return f(r)
+}
+
+// GetClaimsValues returns the claims of the request as bind values, or nil when the binder has no ClaimsProvider.
+// Numbers and booleans are formatted, arrays give one value per element, i.e. the `roles` of a slice field,
+// and nested objects are flattened with the DeepObjectSeparator, i.e. `realm_access.roles`.
+func (b *DefaultBinder) GetClaimsValues(r BindableRequest) (map[string][]string, error) {
+	if b.Claims == nil {
+		return nil, nil
+	}
+	claims, err := b.Claims.Claims(r)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string][]string{}
+	b.flattenValues(values, "", claims)
+	return values, nil
+}
+
+// flattenValues adds the claims, or context values, to the bind values, their names prefixed by the path
+// of the object holding them.
+func (b *DefaultBinder) flattenValues(values map[string][]string, prefix string, entries map[string]interface{}) {
+	for name, value := range entries {
+		key := prefix + name
+		switch value := value.(type) {
+		case nil:
+		case map[string]interface{}:
+			b.flattenValues(values, key+b.DeepObjectSeparator, value)
+		case []interface{}:
+			for _, elem := range value {
+				if elem != nil {
+					values[key] = append(values[key], formatValue(elem))
+				}
+			}
+		case []string:
+			values[key] = append(values[key], value...)
+		default:
+			values[key] = []string{formatValue(value)}
+		}
+	}
+}
+
+// formatValue formats a claim or context value, numbers without exponent so that `exp` fits an int64 field.
+func formatValue(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case json.Number:
+		return value.String()
+	case bool:
+		return strconv.FormatBool(value)
+	}
+	return fmt.Sprint(value)
+}
+
+// BindClaims binds the claims of the ClaimsProvider to fields with the claims tag, i.e. `claims:"sub"`.
+// Only struct destinations are bound, and only when the binder has a ClaimsProvider.
+func (b *DefaultBinder) BindClaims(r BindableRequest, i interface{}) error {
+	if typ := reflect.TypeOf(i); b.Claims == nil || typ == nil || typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	values, err := b.GetClaimsValues(r)
+	if err != nil {
+		return err
+	}
+	ctx, release := b.bindContext(r)
+	defer release()
+	if err := b.bindData(ctx, i, values, b.ClaimsTagName, nil); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package binder
+
+import (
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+)
+
+// ClientIPResolver resolves the real client IP of a request behind trusted proxies.
+// Forwarding headers are only trusted when the request comes from a trusted proxy,
+// and the chain is walked from the right skipping trusted proxies, since the left
+// entries can be set by the client.
+type ClientIPResolver struct {
+	// TrustedProxies are the networks of the proxies allowed to set forwarding headers
+	TrustedProxies []netip.Prefix
+	// Header holding the proxy chain, X-Forwarded-For when empty
+	Header string
+	// UseForwarded reads the chain from the RFC 7239 Forwarded header when present
+	UseForwarded bool
+}
+
+// NewClientIPResolver returns a resolver trusting the given proxy networks or addresses, i.e. `10.0.0.0/8`.
+func NewClientIPResolver(trustedProxies ...string) (*ClientIPResolver, error) {
+	r := &ClientIPResolver{}
+	for _, proxy := range trustedProxies {
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			addr, aerr := netip.ParseAddr(proxy)
+			if aerr != nil {
+				return nil, err
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		r.TrustedProxies = append(r.TrustedProxies, prefix)
+	}
+	return r, nil
+}
+
+// isTrusted reports whether the address belongs to a trusted proxy.
+func (r *ClientIPResolver) isTrusted(addr netip.Addr) bool {
+	for _, prefix := range r.TrustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve returns the client IP of a request from its remote address (`ip:port`) and headers.
+func (r *ClientIPResolver) Resolve(remoteAddr string, headers url.Values) netip.Addr {
+	remote, ok := parseIP(remoteAddr)
+	if !ok || !r.isTrusted(remote) {
+		return remote
+	}
+
+	chain := r.chain(headers)
+	client := remote
+	for i := len(chain) - 1; i >= 0; i-- {
+		addr, ok := parseIP(chain[i])
+		if !ok {
+			// a malformed entry cannot be trusted, stop at the last valid hop
+			break
+		}
+		client = addr
+		if !r.isTrusted(addr) {
+			break
+		}
+	}
+	return client
+}
+
+// chain returns the forwarded addresses from the leftmost (client) to the rightmost (last proxy).
+func (r *ClientIPResolver) chain(headers url.Values) []string {
+	chain := []string{}
+	if r.UseForwarded {
+		for _, value := range headers["Forwarded"] {
+			for _, element := range strings.Split(value, ",") {
+				for _, pair := range strings.Split(element, ";") {
+					if k, v, ok := strings.Cut(strings.TrimSpace(pair), "="); ok && strings.EqualFold(k, "for") {
+						chain = append(chain, strings.Trim(v, `"`))
+					}
+				}
+			}
+		}
+		if len(chain) > 0 {
+			return chain
+		}
+	}
+	header := r.Header
+	if header == "" {
+		header = HeaderXForwardedFor
+	}
+	return splitValues(headers[header], ",")
+}
+
+// parseIP parses an IP optionally followed by a port, in brackets for IPv6 (`[::1]:80`).
+func parseIP(value string) (netip.Addr, bool) {
+	value = strings.TrimSpace(value)
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+	addr, err := netip.ParseAddr(strings.Trim(value, "[]"))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
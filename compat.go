@@ -0,0 +1,33 @@
+package binder
+
+// CompatLevel pins the behaviors of the binder that change between releases, so upgrading the package
+// does not change how existing endpoints bind. The zero value uses DefaultCompatLevel.
+type CompatLevel int
+
+const (
+	CompatDefault CompatLevel = iota // uses DefaultCompatLevel
+	// CompatV1 keeps the original behaviors: map[string]interface{} destinations get the first value of
+	// repeated keys (unless MapMultiValues is enabled), empty values set number and bool fields to zero,
+	// and keys without an exact match are matched case-insensitively.
+	CompatV1
+	// CompatV2 binds repeated keys to map[string]interface{} destinations as []string, leaves number and
+	// bool fields untouched for empty values, and matches keys exactly.
+	CompatV2
+)
+
+// compatLevel returns the effective compatibility level of the binder.
+func (b *DefaultBinder) compatLevel() CompatLevel {
+	if b.CompatLevel != CompatDefault {
+		return b.CompatLevel
+	}
+	if DefaultCompatLevel != CompatDefault {
+		return DefaultCompatLevel
+	}
+	return CompatV1
+}
+
+// caseInsensitiveKeys reports whether the keys of the source match the tag names ignoring case: always for
+// headers, whose names are case-insensitive (`X-Request-Id`, `x-request-id`), and before CompatV2 otherwise.
+func (b *DefaultBinder) caseInsensitiveKeys(tag string) bool {
+	return tag == b.HeaderTagName || b.compatLevel() < CompatV2
+}
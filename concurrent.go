@@ -0,0 +1,23 @@
+package binder
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var bindingDestinations sync.Map // destinations being bound with DetectConcurrentBinds, by pointer
+
+// guardDestination marks the destination as being bound, returning ErrConcurrentBind when another
+// goroutine is binding it, and a function removing the mark.
+func guardDestination(i interface{}) (func(), error) {
+	val := reflect.ValueOf(i)
+	if val.Kind() != reflect.Ptr || val.IsNil() {
+		return func() {}, nil
+	}
+	key := val.Pointer()
+	if _, loaded := bindingDestinations.LoadOrStore(key, struct{}{}); loaded {
+		return func() {}, fmt.Errorf("%w: %s", ErrConcurrentBind, val.Type())
+	}
+	return func() { bindingDestinations.Delete(key) }, nil
+}
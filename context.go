@@ -0,0 +1,200 @@
+package binder
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"slices"
+)
+
+// requestWrapper is implemented by the requests wrapping a bindable request, like the ones
+// created by BindBody and BindWithContext.
+type requestWrapper interface {
+	unwrapRequest() BindableRequest
+}
+
+// findRequest returns the request, or the first request it wraps, implementing T.
+func findRequest[T any](r BindableRequest) (T, bool) {
+	for {
+		if t, ok := r.(T); ok {
+			return t, true
+		}
+		w, ok := r.(requestWrapper)
+		if !ok {
+			var zero T
+			return zero, false
+		}
+		r = w.unwrapRequest()
+	}
+}
+
+// contextRequest replaces the context of a request for BindWithContext, cancelling the reads of its body
+// once the context is done.
+type contextRequest struct {
+	BindableRequest
+	ctx  context.Context
+	body io.ReadCloser
+}
+
+func (r *contextRequest) Context() context.Context {
+	return r.ctx
+}
+
+// GetBody returns the body of the wrapped request, whose reads fail with the context error once it is done.
+func (r *contextRequest) GetBody() io.Reader {
+	if r.body == nil {
+		r.body = cancelableReader(r.ctx, r.BindableRequest.GetBody())
+	}
+	if r.body == nil {
+		return nil
+	}
+	return r.body
+}
+
+func (r *contextRequest) unwrapRequest() BindableRequest {
+	return r.BindableRequest
+}
+
+// close stops reading the body at the end of the bind.
+func (r *contextRequest) close() {
+	if r.body != nil {
+		r.body.Close()
+	}
+}
+
+// cancelableReader returns a reader of the body whose reads fail with the context error once ctx is done.
+// The body is only read by the goroutine of the bind, and is closed when ctx is done before the reader,
+// unblocking a read waiting on a slow client.
+func cancelableReader(ctx context.Context, body io.Reader) io.ReadCloser {
+	if body == nil {
+		return nil
+	}
+	if ctx.Done() == nil {
+		return io.NopCloser(body)
+	}
+	r := &contextReader{ctx: ctx, body: body, stop: func() bool { return false }}
+	if closer, ok := body.(io.Closer); ok {
+		r.stop = context.AfterFunc(ctx, func() { closer.Close() })
+	}
+	return r
+}
+
+// contextReader reads a body until its context is done, see cancelableReader.
+type contextReader struct {
+	ctx  context.Context
+	body io.Reader
+	stop func() bool
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if r.ctx.Err() != nil {
+		return 0, context.Cause(r.ctx)
+	}
+	n, err := r.body.Read(p)
+	if err != nil && r.ctx.Err() != nil {
+		// the read failed as the body was closed by the context
+		return n, context.Cause(r.ctx)
+	}
+	return n, err
+}
+
+// Close stops watching the context at the end of the bind, leaving the body to the caller.
+func (r *contextReader) Close() error {
+	r.stop()
+	return nil
+}
+
+// BindWithContext binds like Bind using ctx instead of the request context: the binding stops with the
+// context error once ctx is done, and so do the reads of the body, i.e. when a slow client sends its body
+// past the request deadline. Form bodies parsed by the request itself are only covered by HttpBinder.
+func (b *DefaultBinder) BindWithContext(ctx context.Context, r BindableRequest, i interface{}) error {
+	cr := &contextRequest{BindableRequest: r, ctx: ctx}
+	defer cr.close()
+	return b.bindAll(cr, i)
+}
+
+// BindWithContext binds like Bind using ctx instead of the request context, see DefaultBinder.BindWithContext.
+// The request body, including form bodies, is read until ctx is done. The forms parsed by the bind are set
+// on r, like Bind does, so the caller can read them and remove the uploaded files.
+func (b *HttpBinder) BindWithContext(ctx context.Context, r *http.Request, i interface{}) error {
+	caller := r
+	r = r.WithContext(ctx)
+	defer func() {
+		// the forms are parsed into the copy of the request
+		caller.Form, caller.PostForm, caller.MultipartForm = r.Form, r.PostForm, r.MultipartForm
+	}()
+	var body io.ReadCloser
+	if r.Body != nil && r.Body != http.NoBody {
+		body = cancelableReader(ctx, r.Body)
+		r.Body = body
+		defer body.Close()
+	}
+	db, ok := b.Binder.(*DefaultBinder)
+	if !ok {
+		return b.Binder.Bind(NewHttpBindableRequest(r), i)
+	}
+	cr := &contextRequest{BindableRequest: NewHttpBindableRequest(r), ctx: ctx, body: body}
+	return db.bindAll(cr, i)
+}
+
+// BindWithContext binds with the default binder using ctx instead of the request context.
+func BindWithContext(ctx context.Context, r BindableRequest, i interface{}) error {
+	if db, ok := GetBinder().(*DefaultBinder); ok {
+		return db.BindWithContext(ctx, r, i)
+	}
+	return GetBinder().Bind(r, i)
+}
+
+// BindHttpWithContext binds an http.Request using ctx instead of the request context.
+func BindHttpWithContext(ctx context.Context, r *http.Request, i interface{}) error {
+	b, err := requestHttpBinder(r)
+	if err != nil {
+		return err
+	}
+	return b.BindWithContext(ctx, r, i)
+}
+
+type binderContextKey struct{}
+
+type bindOptionsContextKey struct{}
+
+// ContextWithBinder returns a copy of ctx carrying the binder used by the BindHttp helpers for the requests
+// with this context, i.e. set by a middleware for the routes needing other limits than the default binder.
+func ContextWithBinder(ctx context.Context, b Binder) context.Context {
+	return context.WithValue(ctx, binderContextKey{}, b)
+}
+
+// ContextWithBindOptions returns a copy of ctx carrying options applied by the BindHttp helpers to the binder
+// of the requests with this context, after the options already carried by ctx.
+func ContextWithBindOptions(ctx context.Context, opts ...BindOption) context.Context {
+	parent, _ := ctx.Value(bindOptionsContextKey{}).([]BindOption)
+	return context.WithValue(ctx, bindOptionsContextKey{}, slices.Concat(parent, opts))
+}
+
+// BinderFromContext returns the binder of ctx set with ContextWithBinder, or the default binder, with the
+// options of ContextWithBindOptions applied when it is a *DefaultBinder.
+func BinderFromContext(ctx context.Context) (Binder, error) {
+	b, ok := ctx.Value(binderContextKey{}).(Binder)
+	if !ok {
+		b = GetBinder()
+	}
+	opts, _ := ctx.Value(bindOptionsContextKey{}).([]BindOption)
+	if db, ok := b.(*DefaultBinder); ok && len(opts) > 0 {
+		return db.withOptions(opts)
+	}
+	return b, nil
+}
+
+// requestHttpBinder returns the binder of the BindHttp helpers for the request, the default one unless
+// its context carries a binder or options.
+func requestHttpBinder(r *http.Request) (*HttpBinder, error) {
+	ctx := r.Context()
+	if ctx.Value(binderContextKey{}) == nil && ctx.Value(bindOptionsContextKey{}) == nil {
+		return GetHttpBinder(), nil
+	}
+	b, err := BinderFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &HttpBinder{Binder: b}, nil
+}
@@ -0,0 +1,320 @@
+package binder
+
+import (
+	"database/sql"
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ConverterFunc converts the raw input values of a field into the destination value.
+// The struct field is passed so converters can read extra options from its tags.
+type ConverterFunc func(values []string, dst reflect.Value, field reflect.StructField) error
+
+// DefaultConverters returns the named converters registered on new binders.
+func DefaultConverters() map[string]ConverterFunc {
+	return map[string]ConverterFunc{
+		"id":       ConvertID,
+		"bytesize": ConvertByteSize,
+		"rate":     ConvertRate,
+	}
+}
+
+// TagOption returns an option of the field: either a dedicated tag, i.e. `layout:"2006-01-02"`,
+// or a `,key=value` option in any other tag of the field, i.e. `query:"from,layout=2006-01-02"`.
+func TagOption(field reflect.StructField, key string) string {
+	if value, ok := field.Tag.Lookup(key); ok {
+		return value
+	}
+	if !strings.Contains(string(field.Tag), "=") {
+		return ""
+	}
+	var result string
+	eachTagValue(field.Tag, func(value string) bool {
+		return tagOptions(value, func(option string) bool {
+			k, v, ok := strings.Cut(option, "=")
+			if ok && strings.TrimSpace(k) == key {
+				result = strings.TrimSpace(v)
+				return true
+			}
+			return false
+		})
+	})
+	return result
+}
+
+// eachTagValue calls fn with the unquoted value of each tag of the field until fn returns true.
+// It reports whether fn returned true.
+func eachTagValue(tags reflect.StructTag, fn func(value string) bool) bool {
+	tag := string(tags)
+	for tag != "" {
+		// skip to the next quoted value, same syntax as reflect.StructTag.Lookup
+		i := strings.Index(tag, `:"`)
+		if i < 0 {
+			break
+		}
+		tag = tag[i+2:]
+		end := 0
+		for end < len(tag) && tag[end] != '"' {
+			if tag[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(tag) {
+			break
+		}
+		value, err := strconv.Unquote(`"` + tag[:end] + `"`)
+		tag = tag[end+1:]
+		if err != nil {
+			continue
+		}
+		if fn(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// tagName returns the input name of the field for the given tag, without `,key=value` options.
+func tagName(field reflect.StructField, tag string) string {
+	name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+	return name
+}
+
+// hasTagFlag reports whether the source tag of the field has the flag option, i.e. `form:"terms,checkbox"`.
+func hasTagFlag(field reflect.StructField, tag string, flag string) bool {
+	return tagOptions(field.Tag.Get(tag), func(option string) bool {
+		return option == flag
+	})
+}
+
+// tagOptions calls fn with each trimmed option of a tag value, after the name, until fn returns true.
+// It reports whether fn returned true, without allocating the options.
+func tagOptions(value string, fn func(option string) bool) bool {
+	_, rest, found := strings.Cut(value, ",")
+	for found {
+		var option string
+		option, rest, found = strings.Cut(rest, ",")
+		if fn(strings.TrimSpace(option)) {
+			return true
+		}
+	}
+	return false
+}
+
+// converterName returns the named converter selected for the field, either with the converter tag,
+// i.e. `convert:"bytesize"`, or with a flag option of the source tag, i.e. `query:"limit,bytesize"`.
+func (b *DefaultBinder) converterName(field reflect.StructField, tag string) string {
+	if name := field.Tag.Get(b.ConverterTagName); name != "" {
+		return name
+	}
+	var name string
+	tagOptions(field.Tag.Get(tag), func(option string) bool {
+		if _, ok := b.Converters[option]; ok {
+			name = option
+			return true
+		}
+		return false
+	})
+	return name
+}
+
+// RegisterConverter registers a named converter selectable with the converter tag, i.e. `convert:"name"`,
+// or with a flag option of the source tag, i.e. `query:"field,name"`.
+func (b *DefaultBinder) RegisterConverter(name string, fn ConverterFunc) {
+	if b.Converters == nil {
+		b.Converters = map[string]ConverterFunc{}
+	}
+	b.Converters[name] = fn
+}
+
+// RegisterTypeConverter registers a converter used for every field of the given type (or a slice of it),
+// taking precedence over BindKeyUnmarshaler, BindUnmarshaler and encoding.TextUnmarshaler implementations.
+func (b *DefaultBinder) RegisterTypeConverter(typ reflect.Type, fn ConverterFunc) {
+	if b.TypeConverters == nil {
+		b.TypeConverters = map[reflect.Type]ConverterFunc{}
+	}
+	b.TypeConverters[typ] = fn
+}
+
+// convertType runs the type converter registered for the field type, if any.
+// Slices of a registered type are converted element by element.
+func (b *DefaultBinder) convertType(values []string, dst reflect.Value, field reflect.StructField) (bool, error) {
+	if len(b.TypeConverters) == 0 {
+		return false, nil
+	}
+	typ := dst.Type()
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if fn, ok := b.TypeConverters[typ]; ok {
+		if dst.Kind() == reflect.Ptr {
+			if dst.IsNil() {
+				dst.Set(reflect.New(typ))
+			}
+			dst = dst.Elem()
+		}
+		return true, fn(values, dst, field)
+	}
+	if typ.Kind() != reflect.Slice {
+		return false, nil
+	}
+	fn, ok := b.TypeConverters[typ.Elem()]
+	if !ok {
+		return false, nil
+	}
+	slice := reflect.MakeSlice(typ, len(values), len(values))
+	for i, v := range values {
+		if err := fn([]string{v}, slice.Index(i), field); err != nil {
+			return true, err
+		}
+	}
+	if dst.Kind() == reflect.Ptr {
+		if dst.IsNil() {
+			dst.Set(reflect.New(typ))
+		}
+		dst = dst.Elem()
+	}
+	dst.Set(slice)
+	return true, nil
+}
+
+// bindsAsValue reports whether a struct field is converted from its input value instead of being
+// bound field by field, i.e. it has a type converter or implements an unmarshaler interface.
+func (b *DefaultBinder) bindsAsValue(field reflect.Value) bool {
+	if _, ok := b.TypeConverters[field.Type()]; ok {
+		return true
+	}
+	switch field.Addr().Interface().(type) {
+	case BindUnmarshaler, BindKeyUnmarshaler, BindContextUnmarshaler, bindMultipleUnmarshaler, encoding.TextUnmarshaler, sql.Scanner, BindEnum, optionalValue:
+		return true
+	}
+	return false
+}
+
+// convert runs the named converter on the field, allocating pointer destinations when needed.
+func (b *DefaultBinder) convert(name string, values []string, dst reflect.Value, field reflect.StructField) error {
+	fn, ok := b.Converters[name]
+	if !ok {
+		return fmt.Errorf("unknown converter %q", name)
+	}
+	if dst.Kind() == reflect.Ptr {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		dst = dst.Elem()
+	}
+	return fn(values, dst, field)
+}
+
+// ConvertID converts the value into a positive, non-zero int64 identifier.
+// The destination may be any integer kind able to hold the value.
+func ConvertID(values []string, dst reflect.Value, _ reflect.StructField) error {
+	if len(values) == 0 {
+		return nil
+	}
+	id, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil || id <= 0 {
+		return fmt.Errorf("%w: %q", ErrInvalidID, values[0])
+	}
+	switch dst.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if dst.OverflowInt(id) {
+			return fmt.Errorf("%w: %q", ErrInvalidID, values[0])
+		}
+		dst.SetInt(id)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if dst.OverflowUint(uint64(id)) {
+			return fmt.Errorf("%w: %q", ErrInvalidID, values[0])
+		}
+		dst.SetUint(uint64(id))
+	default:
+		return fmt.Errorf("id converter cannot bind to %s", dst.Type())
+	}
+	return nil
+}
+
+// ConvertTime converts the value into a time.Time using the layout selected with TagOption(field, "layout"),
+// or the binder TimeLayout when the field has none. The `unix`, `unixmilli` and `unixnano` layouts parse
+// integer timestamps.
+// Values without an explicit offset are interpreted in the location selected with TagOption(field, "tz"),
+// or the binder TimeLocation (UTC when nil), and the result is stored in that location.
+func (b *DefaultBinder) ConvertTime(values []string, dst reflect.Value, field reflect.StructField) error {
+	if len(values) == 0 || values[0] == "" {
+		return nil
+	}
+	value := values[0]
+	layout := TagOption(field, "layout")
+	if layout == "" {
+		layout = b.TimeLayout
+	}
+	loc := b.TimeLocation
+	if tz := TagOption(field, "tz"); tz != "" {
+		var err error
+		if loc, err = loadLocation(tz); err != nil {
+			return err
+		}
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var t time.Time
+	switch layout {
+	case "unix", "unixmilli", "unixnano":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s timestamp %q", layout, value)
+		}
+		switch layout {
+		case "unix":
+			t = time.Unix(n, 0)
+		case "unixmilli":
+			t = time.UnixMilli(n)
+		default:
+			t = time.Unix(0, n)
+		}
+	default:
+		var err error
+		if t, err = time.ParseInLocation(layout, value, loc); err != nil {
+			return err
+		}
+	}
+	dst.Set(reflect.ValueOf(t.In(loc)))
+	return nil
+}
+
+var locations sync.Map // cache of loaded time zones by name
+
+// loadLocation loads the named time zone, caching the result.
+func loadLocation(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tz %q: %w", name, err)
+	}
+	locations.Store(name, loc)
+	return loc, nil
+}
+
+// ConvertNullTime converts the value into a sql.NullTime with the same rules as ConvertTime.
+// An empty value is bound as NULL.
+func (b *DefaultBinder) ConvertNullTime(values []string, dst reflect.Value, field reflect.StructField) error {
+	if len(values) == 0 || values[0] == "" {
+		dst.Set(reflect.ValueOf(sql.NullTime{}))
+		return nil
+	}
+	var t time.Time
+	if err := b.ConvertTime(values, reflect.ValueOf(&t).Elem(), field); err != nil {
+		return err
+	}
+	dst.Set(reflect.ValueOf(sql.NullTime{Time: t, Valid: true}))
+	return nil
+}
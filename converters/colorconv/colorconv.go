@@ -0,0 +1,79 @@
+// Package colorconv registers binder converters for hex colors like `#ff0000`, `#f00` or `#ff000080`.
+// The leading `#` is optional since it must be escaped in URLs.
+//
+// Fields of type Color are converted without a tag, string fields select the converter with
+// `convert:"color"` and receive the normalized `#rrggbb` (or `#rrggbbaa`) form.
+package colorconv
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gobigbang/binder"
+)
+
+// ErrInvalidColor is returned when the value is not a hex color
+var ErrInvalidColor = errors.New("invalid hex color")
+
+// Color is a RGBA color.
+type Color struct {
+	R, G, B, A uint8
+}
+
+// String returns the color in `#rrggbb` form, or `#rrggbbaa` when it is not opaque.
+func (c Color) String() string {
+	if c.A == 0xff {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
+
+// Parse parses a hex color in `#rgb`, `#rgba`, `#rrggbb` or `#rrggbbaa` form.
+func Parse(value string) (Color, error) {
+	s := strings.TrimPrefix(value, "#")
+	if len(s) == 3 || len(s) == 4 {
+		// expand the short form, `f00` is `ff0000`
+		expanded := make([]byte, 0, len(s)*2)
+		for i := 0; i < len(s); i++ {
+			expanded = append(expanded, s[i], s[i])
+		}
+		s = string(expanded)
+	}
+	if len(s) == 6 {
+		s += "ff"
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != 4 {
+		return Color{}, fmt.Errorf("%w: %q", ErrInvalidColor, value)
+	}
+	return Color{R: b[0], G: b[1], B: b[2], A: b[3]}, nil
+}
+
+// Register registers the color converters on the binder.
+func Register(b *binder.DefaultBinder) {
+	b.RegisterConverter("color", Convert)
+	b.RegisterTypeConverter(reflect.TypeOf(Color{}), Convert)
+}
+
+// Convert converts the value into a Color or into a string holding the normalized color.
+func Convert(values []string, dst reflect.Value, _ reflect.StructField) error {
+	if len(values) == 0 || values[0] == "" {
+		return nil
+	}
+	c, err := Parse(values[0])
+	if err != nil {
+		return err
+	}
+	switch {
+	case dst.Type() == reflect.TypeOf(c):
+		dst.Set(reflect.ValueOf(c))
+	case dst.Kind() == reflect.String:
+		dst.SetString(c.String())
+	default:
+		return fmt.Errorf("color converter cannot bind to %s", dst.Type())
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package colorconv_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gobigbang/binder"
+	"github.com/gobigbang/binder/converters/colorconv"
+)
+
+type ColorStruct struct {
+	Background colorconv.Color `query:"bg"`
+	Foreground string          `query:"fg" convert:"color"`
+}
+
+func TestColorConverter(t *testing.T) {
+	b := binder.NewBinder()
+	colorconv.Register(b)
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	req := httptest.NewRequest(http.MethodGet, "/?bg=%23ff000080&fg=0F0", nil)
+	var data ColorStruct
+	if err := httpBinder.BindQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Background != (colorconv.Color{R: 0xff, A: 0x80}) || data.Foreground != "#00ff00" {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?bg=red", nil)
+	if err := httpBinder.BindQueryParams(req, &data); !errors.Is(err, colorconv.ErrInvalidColor) {
+		t.Fatalf("expected ErrInvalidColor, got %v", err)
+	}
+}
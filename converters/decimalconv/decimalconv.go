@@ -0,0 +1,55 @@
+// Package decimalconv registers a binder converter for github.com/shopspring/decimal, so monetary
+// values are parsed from their string form and never pass through float64.
+//
+// The `scale` tag (or `,scale=` option) limits the number of fractional digits accepted:
+//
+//	type Request struct {
+//		Amount decimal.Decimal `form:"amount" scale:"2"` // "10.5" is accepted, "10.505" is not
+//	}
+package decimalconv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/gobigbang/binder"
+	"github.com/shopspring/decimal"
+)
+
+var ScaleTagName = "scale" // tag name holding the maximum number of fractional digits
+
+var (
+	// ErrInvalidDecimal is returned when the value is not a valid decimal number
+	ErrInvalidDecimal = errors.New("invalid decimal")
+	// ErrScale is returned when the value has more fractional digits than the field scale
+	ErrScale = errors.New("too many fractional digits")
+)
+
+// Register registers the decimal type converter on the binder.
+func Register(b *binder.DefaultBinder) {
+	b.RegisterTypeConverter(reflect.TypeOf(decimal.Decimal{}), Convert)
+}
+
+// Convert converts the value into a decimal.Decimal, validating the scale of the field if any.
+func Convert(values []string, dst reflect.Value, field reflect.StructField) error {
+	if len(values) == 0 || values[0] == "" {
+		return nil
+	}
+	d, err := decimal.NewFromString(values[0])
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidDecimal, values[0])
+	}
+	if tag := binder.TagOption(field, ScaleTagName); tag != "" {
+		scale, err := strconv.ParseInt(tag, 10, 32)
+		if err != nil || scale < 0 {
+			return fmt.Errorf("invalid %s tag %q", ScaleTagName, tag)
+		}
+		if !d.Equal(d.Truncate(int32(scale))) {
+			return fmt.Errorf("%w: %q, scale is %d", ErrScale, values[0], scale)
+		}
+	}
+	dst.Set(reflect.ValueOf(d))
+	return nil
+}
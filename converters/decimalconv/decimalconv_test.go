@@ -0,0 +1,42 @@
+package decimalconv_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gobigbang/binder"
+	"github.com/gobigbang/binder/converters/decimalconv"
+	"github.com/shopspring/decimal"
+)
+
+type DecimalStruct struct {
+	Amount decimal.Decimal  `query:"amount" scale:"2"`
+	Rate   *decimal.Decimal `query:"rate"`
+}
+
+func TestDecimalConverter(t *testing.T) {
+	b := binder.NewBinder()
+	decimalconv.Register(b)
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	req := httptest.NewRequest(http.MethodGet, "/?amount=10.50&rate=0.1234567890123456789", nil)
+	var data DecimalStruct
+	if err := httpBinder.BindQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Amount.String() != "10.5" || data.Rate == nil || data.Rate.String() != "0.1234567890123456789" {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?amount=10.505", nil)
+	if err := httpBinder.BindQueryParams(req, &data); !errors.Is(err, decimalconv.ErrScale) {
+		t.Fatalf("expected ErrScale, got %v", err)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?amount=ten", nil)
+	if err := httpBinder.BindQueryParams(req, &data); !errors.Is(err, decimalconv.ErrInvalidDecimal) {
+		t.Fatalf("expected ErrInvalidDecimal, got %v", err)
+	}
+}
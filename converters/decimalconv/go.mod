@@ -0,0 +1,10 @@
+module github.com/gobigbang/binder/converters/decimalconv
+
+go 1.23.2
+
+require (
+	github.com/gobigbang/binder v0.0.0
+	github.com/shopspring/decimal v1.4.0
+)
+
+replace github.com/gobigbang/binder => ../..
@@ -0,0 +1,76 @@
+// Package geoconv registers a binder converter for geographic coordinates.
+//
+// A Point is bound either from a single `lat,lng` value (`?at=41.9,12.5`) or from two
+// repeated values (`?at=41.9&at=12.5`).
+package geoconv
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gobigbang/binder"
+)
+
+// ErrInvalidPoint is returned when the value is not a valid lat/lng pair
+var ErrInvalidPoint = errors.New("invalid coordinates")
+
+// Point is a WGS 84 coordinate pair.
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+// Parse parses a `lat,lng` pair.
+func Parse(value string) (Point, error) {
+	lat, lng, ok := strings.Cut(value, ",")
+	if !ok {
+		return Point{}, fmt.Errorf("%w: %q", ErrInvalidPoint, value)
+	}
+	return parsePair(lat, lng)
+}
+
+// parsePair parses and validates the latitude and longitude.
+func parsePair(lat, lng string) (Point, error) {
+	var p Point
+	var err1, err2 error
+	p.Lat, err1 = strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	p.Lng, err2 = strconv.ParseFloat(strings.TrimSpace(lng), 64)
+	if err1 != nil || err2 != nil || !finite(p.Lat) || !finite(p.Lng) || p.Lat < -90 || p.Lat > 90 || p.Lng < -180 || p.Lng > 180 {
+		return Point{}, fmt.Errorf("%w: %q", ErrInvalidPoint, lat+","+lng)
+	}
+	return p, nil
+}
+
+// finite reports whether the coordinate is neither NaN nor infinite: NaN fails every comparison, so the
+// range checks alone would accept it.
+func finite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// Register registers the Point type converter on the binder.
+func Register(b *binder.DefaultBinder) {
+	b.RegisterTypeConverter(reflect.TypeOf(Point{}), Convert)
+}
+
+// Convert converts a `lat,lng` value, or two values holding the latitude and the longitude, into a Point.
+func Convert(values []string, dst reflect.Value, _ reflect.StructField) error {
+	var p Point
+	var err error
+	switch len(values) {
+	case 0:
+		return nil
+	case 2:
+		p, err = parsePair(values[0], values[1])
+	default:
+		p, err = Parse(values[0])
+	}
+	if err != nil {
+		return err
+	}
+	dst.Set(reflect.ValueOf(p))
+	return nil
+}
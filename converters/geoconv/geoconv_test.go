@@ -0,0 +1,50 @@
+package geoconv_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gobigbang/binder"
+	"github.com/gobigbang/binder/converters/geoconv"
+)
+
+type GeoStruct struct {
+	From geoconv.Point  `query:"from"`
+	To   *geoconv.Point `query:"to"`
+}
+
+func TestPointConverter(t *testing.T) {
+	b := binder.NewBinder()
+	geoconv.Register(b)
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	req := httptest.NewRequest(http.MethodGet, "/?from=41.9,12.5&to=45.4&to=9.2", nil)
+	var data GeoStruct
+	if err := httpBinder.BindQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.From != (geoconv.Point{Lat: 41.9, Lng: 12.5}) || data.To == nil || *data.To != (geoconv.Point{Lat: 45.4, Lng: 9.2}) {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?from=91,0", nil)
+	if err := httpBinder.BindQueryParams(req, &data); !errors.Is(err, geoconv.ErrInvalidPoint) {
+		t.Fatalf("expected ErrInvalidPoint, got %v", err)
+	}
+}
+
+func TestPointConverterRejectsNonFinite(t *testing.T) {
+	b := binder.NewBinder()
+	geoconv.Register(b)
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	for _, query := range []string{"from=NaN,0", "from=0,NaN", "from=Inf,0", "from=0,-Inf", "to=NaN&to=NaN"} {
+		req := httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+		var data GeoStruct
+		if err := httpBinder.BindQueryParams(req, &data); !errors.Is(err, geoconv.ErrInvalidPoint) {
+			t.Fatalf("expected ErrInvalidPoint for %s, got %v", query, err)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/gobigbang/binder/converters/i18nconv
+
+go 1.23.2
+
+require (
+	github.com/gobigbang/binder v0.0.0
+	golang.org/x/text v0.28.0
+)
+
+replace github.com/gobigbang/binder => ../..
@@ -0,0 +1,93 @@
+// Package i18nconv registers binder converters for ISO 3166 country codes, ISO 4217 currency
+// codes and BCP 47 language tags, backed by golang.org/x/text.
+//
+// String fields select a converter with the convert tag and receive the canonical code:
+//
+//	type Request struct {
+//		Country  string `query:"country" convert:"country"`   // "it", "ITA" and "380" become "IT"
+//		Currency string `query:"currency" convert:"currency"` // "eur" becomes "EUR"
+//		Language string `query:"lang" convert:"language"`     // "en-us" becomes "en-US"
+//	}
+//
+// Fields of type language.Tag, language.Region and currency.Unit are converted without a tag.
+package i18nconv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/gobigbang/binder"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+)
+
+var (
+	// ErrInvalidCountry is returned when the value is not an ISO 3166-1 country code
+	ErrInvalidCountry = errors.New("invalid country code")
+	// ErrInvalidCurrency is returned when the value is not an ISO 4217 currency code
+	ErrInvalidCurrency = errors.New("invalid currency code")
+	// ErrInvalidLanguage is returned when the value is not a well-formed BCP 47 language tag
+	ErrInvalidLanguage = errors.New("invalid language tag")
+)
+
+// Register registers the named and type converters on the binder.
+func Register(b *binder.DefaultBinder) {
+	b.RegisterConverter("country", ConvertCountry)
+	b.RegisterConverter("currency", ConvertCurrency)
+	b.RegisterConverter("language", ConvertLanguage)
+	b.RegisterTypeConverter(reflect.TypeOf(language.Region{}), ConvertCountry)
+	b.RegisterTypeConverter(reflect.TypeOf(currency.Unit{}), ConvertCurrency)
+	b.RegisterTypeConverter(reflect.TypeOf(language.Tag{}), ConvertLanguage)
+}
+
+// ConvertCountry converts an alpha-2, alpha-3 or numeric ISO 3166-1 code into a string
+// holding the alpha-2 code or into a language.Region.
+func ConvertCountry(values []string, dst reflect.Value, _ reflect.StructField) error {
+	if len(values) == 0 || values[0] == "" {
+		return nil
+	}
+	region, err := language.ParseRegion(values[0])
+	if err != nil || !region.IsCountry() {
+		return fmt.Errorf("%w: %q", ErrInvalidCountry, values[0])
+	}
+	return set(dst, region, region.String())
+}
+
+// ConvertCurrency converts an ISO 4217 code into a string holding the upper case code or into a currency.Unit.
+func ConvertCurrency(values []string, dst reflect.Value, _ reflect.StructField) error {
+	if len(values) == 0 || values[0] == "" {
+		return nil
+	}
+	unit, err := currency.ParseISO(values[0])
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidCurrency, values[0])
+	}
+	return set(dst, unit, unit.String())
+}
+
+// ConvertLanguage converts a BCP 47 language tag into a string holding the canonical tag or into a language.Tag.
+func ConvertLanguage(values []string, dst reflect.Value, _ reflect.StructField) error {
+	if len(values) == 0 || values[0] == "" {
+		return nil
+	}
+	tag, err := language.Parse(values[0])
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidLanguage, values[0])
+	}
+	return set(dst, tag, tag.String())
+}
+
+// set assigns the parsed value when the destination has its type, or its canonical string form otherwise.
+func set(dst reflect.Value, value interface{}, canonical string) error {
+	v := reflect.ValueOf(value)
+	switch {
+	case v.Type().AssignableTo(dst.Type()):
+		dst.Set(v)
+	case dst.Kind() == reflect.String:
+		dst.SetString(canonical)
+	default:
+		return fmt.Errorf("cannot bind %s to %s", v.Type(), dst.Type())
+	}
+	return nil
+}
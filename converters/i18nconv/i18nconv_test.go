@@ -0,0 +1,47 @@
+package i18nconv_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gobigbang/binder"
+	"github.com/gobigbang/binder/converters/i18nconv"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+)
+
+type I18nStruct struct {
+	Country  string        `query:"country" convert:"country"`
+	Currency string        `query:"currency" convert:"currency"`
+	Language string        `query:"lang" convert:"language"`
+	Tag      language.Tag  `query:"tag"`
+	Unit     currency.Unit `query:"unit"`
+}
+
+func TestI18nConverters(t *testing.T) {
+	b := binder.NewBinder()
+	i18nconv.Register(b)
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	req := httptest.NewRequest(http.MethodGet, "/?country=ITA&currency=eur&lang=en-us&tag=pt-br&unit=usd", nil)
+	var data I18nStruct
+	if err := httpBinder.BindQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.Country != "IT" || data.Currency != "EUR" || data.Language != "en-US" || data.Tag != language.BrazilianPortuguese || data.Unit != currency.USD {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+
+	for query, expected := range map[string]error{
+		"/?country=XX":   i18nconv.ErrInvalidCountry,
+		"/?currency=ABC": i18nconv.ErrInvalidCurrency,
+		"/?lang=e":       i18nconv.ErrInvalidLanguage,
+	} {
+		req := httptest.NewRequest(http.MethodGet, query, nil)
+		if err := httpBinder.BindQueryParams(req, &data); !errors.Is(err, expected) {
+			t.Fatalf("expected %v for %s, got %v", expected, query, err)
+		}
+	}
+}
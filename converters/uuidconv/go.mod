@@ -0,0 +1,11 @@
+module github.com/gobigbang/binder/converters/uuidconv
+
+go 1.23.2
+
+require (
+	github.com/gobigbang/binder v0.0.0
+	github.com/gofrs/uuid/v5 v5.3.2
+	github.com/google/uuid v1.6.0
+)
+
+replace github.com/gobigbang/binder => ../..
@@ -0,0 +1,83 @@
+// Package uuidconv registers binder converters for github.com/google/uuid and github.com/gofrs/uuid types.
+//
+// Fields of type uuid.UUID (or pointers and slices of it) are parsed and, when the field has a
+// `uuidver` tag, validated to be of the given version and of the RFC 4122 variant:
+//
+//	type Request struct {
+//		ID uuid.UUID `param:"id" uuidver:"4"`
+//	}
+package uuidconv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/gobigbang/binder"
+	gofrs "github.com/gofrs/uuid/v5"
+	google "github.com/google/uuid"
+)
+
+var VersionTagName = "uuidver" // tag name holding the required UUID version
+
+var (
+	// ErrInvalidUUID is returned when the value is not a valid UUID
+	ErrInvalidUUID = errors.New("invalid uuid")
+	// ErrUUIDVersion is returned when the UUID does not match the required version or variant
+	ErrUUIDVersion = errors.New("unexpected uuid version")
+)
+
+// Register registers the UUID type converters on the binder.
+func Register(b *binder.DefaultBinder) {
+	b.RegisterTypeConverter(reflect.TypeOf(google.UUID{}), ConvertGoogle)
+	b.RegisterTypeConverter(reflect.TypeOf(gofrs.UUID{}), ConvertGofrs)
+}
+
+// ConvertGoogle converts the value into a github.com/google/uuid UUID.
+func ConvertGoogle(values []string, dst reflect.Value, field reflect.StructField) error {
+	if len(values) == 0 || values[0] == "" {
+		return nil
+	}
+	id, err := google.Parse(values[0])
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidUUID, values[0])
+	}
+	if err := checkVersion(field, int(id.Version()), id.Variant() == google.RFC4122); err != nil {
+		return err
+	}
+	dst.Set(reflect.ValueOf(id))
+	return nil
+}
+
+// ConvertGofrs converts the value into a github.com/gofrs/uuid UUID.
+func ConvertGofrs(values []string, dst reflect.Value, field reflect.StructField) error {
+	if len(values) == 0 || values[0] == "" {
+		return nil
+	}
+	id, err := gofrs.FromString(values[0])
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidUUID, values[0])
+	}
+	if err := checkVersion(field, int(id.Version()), id.Variant() == gofrs.VariantRFC4122); err != nil {
+		return err
+	}
+	dst.Set(reflect.ValueOf(id))
+	return nil
+}
+
+// checkVersion validates the UUID version and variant against the `uuidver` tag or option of the field.
+func checkVersion(field reflect.StructField, version int, rfc4122 bool) error {
+	tag := binder.TagOption(field, VersionTagName)
+	if tag == "" {
+		return nil
+	}
+	expected, err := strconv.Atoi(tag)
+	if err != nil {
+		return fmt.Errorf("invalid %s tag %q", VersionTagName, tag)
+	}
+	if version != expected || !rfc4122 {
+		return fmt.Errorf("%w: expected version %d, got %d", ErrUUIDVersion, expected, version)
+	}
+	return nil
+}
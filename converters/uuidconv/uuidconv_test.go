@@ -0,0 +1,46 @@
+package uuidconv_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gobigbang/binder"
+	"github.com/gobigbang/binder/converters/uuidconv"
+	gofrs "github.com/gofrs/uuid/v5"
+	google "github.com/google/uuid"
+)
+
+type UUIDStruct struct {
+	ID    google.UUID   `query:"id" uuidver:"4"`
+	Other *gofrs.UUID   `query:"other"`
+	IDs   []google.UUID `query:"ids"`
+}
+
+func TestUUIDConverters(t *testing.T) {
+	b := binder.NewBinder()
+	uuidconv.Register(b)
+	httpBinder := &binder.HttpBinder{Binder: b}
+
+	v4 := google.New()
+	v7 := google.Must(google.NewV7())
+	req := httptest.NewRequest(http.MethodGet, "/?id="+v4.String()+"&other="+v7.String()+"&ids="+v4.String()+"&ids="+v7.String(), nil)
+	var data UUIDStruct
+	if err := httpBinder.BindQueryParams(req, &data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if data.ID != v4 || data.Other == nil || data.Other.String() != v7.String() || len(data.IDs) != 2 || data.IDs[1] != v7 {
+		t.Fatalf("expected data to be bound correctly, got %+v", data)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?id="+v7.String(), nil)
+	if err := httpBinder.BindQueryParams(req, &data); !errors.Is(err, uuidconv.ErrUUIDVersion) {
+		t.Fatalf("expected ErrUUIDVersion, got %v", err)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?id=not-a-uuid", nil)
+	if err := httpBinder.BindQueryParams(req, &data); !errors.Is(err, uuidconv.ErrInvalidUUID) {
+		t.Fatalf("expected ErrInvalidUUID, got %v", err)
+	}
+}
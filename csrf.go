@@ -0,0 +1,91 @@
+package binder
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// CSRF values bound with the csrf tag, i.e. `csrf:"token"`.
+const (
+	CSRFToken  = "token"  // submitted token, from the header or else the form field
+	CSRFHeader = "header" // token sent in the configured header
+	CSRFForm   = "form"   // token sent in the configured form field
+	CSRFCookie = "cookie" // token stored in the configured cookie, for double submit checks
+)
+
+// CSRFTokenSource configures where the binder reads the CSRF token from.
+// Empty names disable the corresponding location.
+type CSRFTokenSource struct {
+	FormField string // form field, i.e. `csrf_token`
+	Header    string // header, i.e. `X-CSRF-Token`
+	Cookie    string // cookie, i.e. `csrf_token`
+	// Consume removes the form field from the form values before binding, so it is not bound
+	// into destination maps nor reported as an unknown key.
+	Consume bool
+}
+
+// NewCSRFTokenSource returns a source reading the token from the form field, header and cookie names.
+func NewCSRFTokenSource(formField string, header string, cookie string) *CSRFTokenSource {
+	return &CSRFTokenSource{FormField: formField, Header: header, Cookie: cookie}
+}
+
+// GetCSRFValues returns the CSRF token values of the request, or nil when the binder has no CSRF source.
+// The form field is read from the form body alone, so a token in the URL query (which ends up in logs and
+// referrers) is ignored. When the cookie is repeated the first one is used, like http.Request.Cookie.
+func (b *DefaultBinder) GetCSRFValues(r BindableRequest) map[string][]string {
+	if b.CSRF == nil {
+		return nil
+	}
+	values := map[string][]string{}
+	if b.CSRF.Header != "" {
+		if token := http.Header(r.GetHeaders()).Get(b.CSRF.Header); token != "" {
+			values[CSRFHeader] = []string{token}
+		}
+	}
+	if b.CSRF.FormField != "" {
+		if token := b.postFormValues(r)[b.CSRF.FormField]; len(token) > 0 && token[0] != "" {
+			values[CSRFForm] = token[:1]
+		}
+	}
+	if b.CSRF.Cookie != "" {
+		if cookie, ok := firstCookie(r.GetHeaders(), b.CSRF.Cookie); ok {
+			values[CSRFCookie] = []string{cookie}
+		}
+	}
+	if token, ok := values[CSRFHeader]; ok {
+		values[CSRFToken] = token
+	} else if token, ok := values[CSRFForm]; ok {
+		values[CSRFToken] = token
+	}
+	return values
+}
+
+// firstCookie returns the value of the first cookie with the name in the Cookie headers.
+func firstCookie(headers map[string][]string, name string) (string, bool) {
+	for _, line := range http.Header(headers).Values("Cookie") {
+		cookies, err := http.ParseCookie(line)
+		if err != nil {
+			continue
+		}
+		for _, cookie := range cookies {
+			if cookie.Name == name {
+				return cookie.Value, true
+			}
+		}
+	}
+	return "", false
+}
+
+// BindCSRF binds the CSRF token to fields with the csrf tag, i.e. `csrf:"token"` or `csrf:"cookie"`.
+// Only struct destinations are bound, and only when the binder has a CSRF source.
+func (b *DefaultBinder) BindCSRF(r BindableRequest, i interface{}) error {
+	if typ := reflect.TypeOf(i); b.CSRF == nil || typ == nil || typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	ctx, release := b.bindContext(r)
+	defer release()
+	if err := b.bindData(ctx, i, b.GetCSRFValues(r), b.CSRFTagName, nil); err != nil {
+		return err
+	}
+	return nil
+}
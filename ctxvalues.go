@@ -0,0 +1,69 @@
+package binder
+
+import (
+	"context"
+	"reflect"
+)
+
+// ContextValuesProvider returns the values placed in the request context by middlewares (request id,
+// authenticated user, tenant...) by the name they are bound with the ctx tag, i.e. `ctx:"request_id"`.
+type ContextValuesProvider func(ctx context.Context) map[string]interface{}
+
+// BindContextValues binds the values of the ContextValuesProvider to fields with the ctx tag. Values assignable
+// to their field, like a *User, are set as is; the others are formatted and converted like the claims.
+// Only struct destinations are bound, and only when the binder has a ContextValuesProvider.
+func (b *DefaultBinder) BindContextValues(r BindableRequest, i interface{}) error {
+	if typ := reflect.TypeOf(i); b.ContextValues == nil || typ == nil || typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	return b.bindServerValues(r, i, b.ContextValues(RequestContext(r)), b.ContextTagName)
+}
+
+// bindServerValues binds values held by the server, like the context or session values, to the struct fields
+// with the tag: the values assignable to their field are set as is, the others are formatted and converted.
+func (b *DefaultBinder) bindServerValues(r BindableRequest, i interface{}, entries map[string]interface{}, tag string) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	assigned := map[string]bool{}
+	b.assignValues(reflect.ValueOf(i).Elem(), entries, tag, assigned)
+	remaining := map[string]interface{}{}
+	for name, value := range entries {
+		if !assigned[name] {
+			remaining[name] = value
+		}
+	}
+	values := map[string][]string{}
+	b.flattenValues(values, "", remaining)
+	ctx, release := b.bindContext(r)
+	defer release()
+	if err := b.bindData(ctx, i, values, tag, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// assignValues sets the fields of the struct, and of its embedded structs, whose value is assignable to
+// their type, adding the names of the values to assigned.
+func (b *DefaultBinder) assignValues(val reflect.Value, entries map[string]interface{}, tag string, assigned map[string]bool) {
+	typ := val.Type()
+	for index := 0; index < typ.NumField(); index++ {
+		typeField := typ.Field(index)
+		field := val.Field(index)
+		if typeField.Anonymous && field.Kind() == reflect.Struct && field.CanSet() {
+			b.assignValues(field, entries, tag, assigned)
+			continue
+		}
+		if !field.CanSet() || b.isExcluded(typeField, tag) || isReadOnly(typeField) {
+			continue
+		}
+		name := tagName(typeField, tag)
+		value, ok := entries[name]
+		if name == "" || !ok || value == nil || !reflect.TypeOf(value).AssignableTo(typeField.Type) {
+			continue
+		}
+		field.Set(reflect.ValueOf(value))
+		b.markPresent(field)
+		assigned[name] = true
+	}
+}
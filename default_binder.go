@@ -1,30 +1,139 @@
 package binder
 
 import (
+	"context"
+	"database/sql"
+	"encoding/xml"
 	"errors"
+	"fmt"
+	"maps"
+	"math/big"
 	"mime/multipart"
+	"net"
+	"net/netip"
 	"net/url"
 	"reflect"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 )
 
 // DefaultBinder is the default implementation of the `Binder` interface.
+// Its settings are meant to be configured before the binder is shared: per-route binders are derived
+// with Clone and the With methods (WithMaxBodySize...) instead of mutating a shared binder.
 type DefaultBinder struct {
 	JSONSerializer       JSONSerializer
 	XMLSerializer        XMLSerializer
+	Deserializers        map[string]Deserializer
+	BodyProcessors       map[string][]BodyProcessor // processors wrapping the body by media type, "" for all, see UseBodyProcessor
 	PathMatcher          *regexp.Regexp
 	ArrayMatcher         *regexp.Regexp
 	MapMatcher           *regexp.Regexp
 	ArrayNotationMatcher *regexp.Regexp
+	DeepObjectMatcher    *regexp.Regexp // matcher for OpenAPI deepObject query params, nil to use the form matchers
 	DeepObjectSeparator  string
 	MaxBodySize          int64
 	MaxArraySize         int
+	SparseArrayPolicy    SparseArrayPolicy // how gaps in indexed notation are bound, zero filled by default
+	ArraySizePolicy      ArraySizePolicy   // how indices exceeding MaxArraySize are handled, an error by default
+	MergePolicy          MergePolicy       // how the sources of Bind set the fields set by earlier ones, last wins by default
+	StringSizePolicy     StringSizePolicy  // how values exceeding the `maxbytes` of their field are handled, an error by default
+	ReadOnlyPolicy       ReadOnlyPolicy    // how client values of `,readonly` fields are handled, ignored by default
+	CompatLevel          CompatLevel       // behaviors pinned across releases, DefaultCompatLevel when zero
+	MaxHeaderValues      int               // max number of header values, 0 for no limit
+	MaxHeaderBytes       int64             // max total size of header keys and values, 0 for no limit
 	HeaderTagName        string
 	FormTagName          string
 	QueryTagName         string
 	ParamTagName         string
-	BindOrder            []BindFunc
+	XMLTagName           string
+	BindTagName          string // tag excluding a field from all the sources with `bind:"-"`
+	RequestTagName       string
+	CSRFTagName          string
+	AuthTagName          string // tag of the Authorization credentials, i.e. `auth:"bearer"`
+	ClaimsTagName        string // tag of the claims of the ClaimsProvider, i.e. `claims:"sub"`
+	ContextTagName       string // tag of the context values, i.e. `ctx:"request_id"`
+	SessionTagName       string // tag of the session values, i.e. `session:"cart_id"`
+	EnvTagName           string // tag of the environment fallback values, i.e. `env:"FEATURE_SEARCH"`
+	ConverterTagName     string
+	SensitiveTagName     string // tag marking the fields holding secrets with `sensitive:"true"`, redacted from the errors
+	Converters           map[string]ConverterFunc
+	TypeConverters       map[reflect.Type]ConverterFunc
+	TimeLayout           string
+	TimeLocation         *time.Location
+	ClientIPResolver     *ClientIPResolver
+	CSRF                 *CSRFTokenSource      // where the csrf tag reads the token from, nil to disable
+	Claims               ClaimsProvider        // verified claims bound with the claims tag, nil to disable
+	ContextValues        ContextValuesProvider // context values bound with the ctx tag, nil to disable
+	Session              SessionProvider       // session values bound with the session tag, nil to disable
+	Env                  EnvLookup             // fallback values bound with the env tag, i.e. os.LookupEnv, nil to disable
+	BindOrder            []string              // names of the sources bound by Bind, in order, i.e. SourceQuery
+	BindSources          map[string]BindFunc   // sources registered with RegisterBindSource, see BindSelected
+	BeforeBind           []BindFunc            // hooks run before every Bind, see OnBeforeBind
+	AfterBind            []BindFunc            // hooks run after every successful Bind, see OnAfterBind
+	QueryNormalizers     []QueryNormalizer
+	MethodOverrideField  string // form field overriding the method of POST requests, i.e. `_method`, empty to disable
+	// AllowFloatExponent accepts scientific notation (`1e9`) and hex floats in float fields
+	AllowFloatExponent bool
+	// AllowFloatNonFinite accepts `Inf` and `NaN` in float fields
+	AllowFloatNonFinite bool
+	// TrimStrings trims leading and trailing spaces of all the values before conversion,
+	// like the `,trim` option does for a single field
+	TrimStrings bool
+	// SplitHeaderValues splits comma separated header values bound to slice fields,
+	// i.e. `X-Forwarded-For: 10.0.0.1, 10.0.0.2`
+	SplitHeaderValues bool
+	// SpaceAsPlusSign reads a leading space of number values as a plus sign, for the clients sending
+	// `?delta=+5` unencoded, which URL decoding turns into ` 5`
+	SpaceAsPlusSign bool
+	// LenientBool accepts `on`/`off`, `yes`/`no` and `y`/`n` in bool fields
+	LenientBool bool
+	// FormCheckboxes binds bool fields as false when their key is missing from form data,
+	// like the `,checkbox` option does for a single field, i.e. `form:"terms,checkbox"`
+	FormCheckboxes bool
+	// StrictKeys rejects query and form keys that do not match any tagged field.
+	// Note that urlencoded form data also contains the URL query keys unless FormBodyOnly is enabled.
+	StrictKeys bool
+	// StrictAmbiguousFields returns an *AmbiguousFieldError when embedded structs declare the same input name
+	// at the same depth, instead of binding none of them like encoding/json does
+	StrictAmbiguousFields bool
+	// StrictFields returns an *UnsettableFieldError when a field tagged for the source cannot be set,
+	// i.e. it is unexported, instead of skipping it
+	StrictFields bool
+	// UseFieldNameFallback binds the exported fields without any tag from the param, query, form and
+	// header keys matching their name, case-insensitive and ignoring `_` and `-`, i.e. `user_id` or
+	// `userId` for `UserID`. Untagged struct fields are still bound from the keys of the parent struct.
+	UseFieldNameFallback bool
+	// NameMapper maps the name of the exported fields without any tag to the param, query, form and
+	// header key they are bound from, i.e. SnakeCaseName. It takes precedence over UseFieldNameFallback.
+	NameMapper func(fieldName string) string
+	// BodyRequired fails BindBody with ErrEmptyBody when the body is empty, see RequireBody
+	BodyRequired bool
+	// UseJSONTagFallback binds query and form values to the fields without a query or form tag
+	// by their json tag name, like gin does
+	UseJSONTagFallback bool
+	// DetectConcurrentBinds (debug) fails Bind and BindSelected with ErrConcurrentBind when another goroutine
+	// is binding the same destination pointer, turning the data races of shared destinations into errors
+	DetectConcurrentBinds bool
+	// UseScratchPool (experimental) serves the intermediate maps of each bind from a pool released
+	// at the end of the bind, reducing the allocations of deeply nested forms and queries
+	UseScratchPool bool
+	// MapMultiValues binds repeated keys to map[string]interface{} destinations as []string
+	// instead of their first value, which CompatV2 always does
+	MapMultiValues bool
+	// XMLParamOverlay binds the path and query params onto the fields decoded from an XML body
+	// using their xml names, including attributes and `a>b` paths, i.e. `?address.city=Rome`
+	XMLParamOverlay bool
+	// FormBodyOnly binds urlencoded form data from the body alone, keeping the URL query values
+	// (merged into the form by ParseForm) to the query source. Requires a PostFormRequest.
+	FormBodyOnly bool
+
+	sourced   sourcedFields        // sources that set the fields declaring their sources, on the copy running a Bind
+	optionErr error                // error of the BindOption applied to the copy running a BindWith
+	report    *BindReport          // report filled by the copy running a BindWith, see WithReport
+	present   map[presenceKey]bool // fields whose key was supplied during a step, when a report is requested
 }
 
 func NewBinder() *DefaultBinder {
@@ -36,44 +145,146 @@ func NewBinder() *DefaultBinder {
 		MapMatcher:           MapMatcherRegexp,
 		ArrayMatcher:         ArrayMatcherRegexp,
 		ArrayNotationMatcher: ArrayNotationRegexp,
+		DeepObjectMatcher:    DeepObjectRegexp,
 		MaxArraySize:         MaxArraySize,
+		MaxHeaderValues:      DefaultMaxHeaderValues,
+		MaxHeaderBytes:       DefaultMaxHeaderBytes,
 		HeaderTagName:        DefaultHeaderTagName,
 		FormTagName:          DefaultFormTagName,
 		QueryTagName:         DefaultQueryTagName,
 		ParamTagName:         DefaultParamTagName,
+		XMLTagName:           DefaultXMLTagName,
+		BindTagName:          DefaultBindTagName,
+		RequestTagName:       DefaultRequestTagName,
+		CSRFTagName:          DefaultCSRFTagName,
+		AuthTagName:          DefaultAuthTagName,
+		ClaimsTagName:        DefaultClaimsTagName,
+		ContextTagName:       DefaultContextTagName,
+		SessionTagName:       DefaultSessionTagName,
+		EnvTagName:           DefaultEnvTagName,
+		ConverterTagName:     DefaultConverterTagName,
+		SensitiveTagName:     DefaultSensitiveTagName,
+		Converters:           DefaultConverters(),
+		TimeLayout:           DefaultTimeLayout,
 		DeepObjectSeparator:  DefaultDeepObjectSeparator,
-		BindOrder:            []BindFunc{},
+		BindOrder:            []string{},
 	}
 
-	r.BindOrder = []BindFunc{
-		r.BindPathParams,
-		r.BindQueryParams,
-		r.BindBody,
+	r.RegisterTypeConverter(reflect.TypeOf(time.Time{}), r.ConvertTime)
+	r.RegisterTypeConverter(reflect.TypeOf(sql.NullTime{}), r.ConvertNullTime)
+	r.RegisterTypeConverter(reflect.TypeOf(ByteSize(0)), ConvertByteSize)
+	r.RegisterTypeConverter(reflect.TypeOf(Rate(0)), ConvertRate)
+	r.RegisterTypeConverter(reflect.TypeOf(big.Int{}), ConvertBigInt)
+	r.RegisterTypeConverter(reflect.TypeOf(big.Float{}), r.ConvertBigFloat)
+	r.RegisterTypeConverter(reflect.TypeOf(netip.Addr{}), ConvertAddr)
+	r.RegisterTypeConverter(reflect.TypeOf(netip.Prefix{}), ConvertPrefix)
+	r.RegisterTypeConverter(urlType, ConvertURL)
+	r.RegisterTypeConverter(mailAddressType, ConvertMailAddress)
+	r.RegisterTypeConverter(reflect.TypeOf(net.IP{}), ConvertIP)
+	r.RegisterTypeConverter(reflect.TypeOf(net.IPNet{}), ConvertIPNet)
+
+	r.BindOrder = []string{
+		// first, so any request value overrides the fallback values
+		SourceEnv,
+		SourceCSRF,
+		SourceAuth,
+		SourcePath,
+		SourceQuery,
+		SourceBody,
+		// last, so the values of the server win over the request values
+		SourceClaims,
+		SourceSession,
+		SourceContext,
 	}
+	WithBindRequestMetadata(DefaultBindRequestMetadata)(r)
+	WithBindHeaders(DefaultBindHeaders)(r)
 
 	return r
 }
 
+// clone returns a copy of the binder with its own maps and slices. The binder method values found in
+// TypeConverters (ConvertTime...) are bound to the copy, so its settings apply; the sources of BindOrder
+// are looked up by name on the binder running the bind.
+func (b *DefaultBinder) clone() *DefaultBinder {
+	c := *b
+	c.Deserializers = maps.Clone(b.Deserializers)
+	if b.BodyProcessors != nil {
+		c.BodyProcessors = make(map[string][]BodyProcessor, len(b.BodyProcessors))
+		for mediaType, processors := range b.BodyProcessors {
+			c.BodyProcessors[mediaType] = slices.Clone(processors)
+		}
+	}
+	c.Converters = maps.Clone(b.Converters)
+	c.QueryNormalizers = slices.Clone(b.QueryNormalizers)
+	c.BeforeBind = slices.Clone(b.BeforeBind)
+	c.AfterBind = slices.Clone(b.AfterBind)
+
+	c.BindOrder = slices.Clone(b.BindOrder)
+	c.BindSources = maps.Clone(b.BindSources)
+
+	converters := map[uintptr]ConverterFunc{}
+	for _, fn := range []ConverterFunc{c.ConvertTime, c.ConvertNullTime, c.ConvertBigFloat} {
+		converters[reflect.ValueOf(fn).Pointer()] = fn
+	}
+	if b.TypeConverters != nil {
+		c.TypeConverters = make(map[reflect.Type]ConverterFunc, len(b.TypeConverters))
+		for typ, fn := range b.TypeConverters {
+			if own, ok := converters[reflect.ValueOf(fn).Pointer()]; ok {
+				fn = own
+			}
+			c.TypeConverters[typ] = fn
+		}
+	}
+	return &c
+}
+
+// RequireBody returns a copy of the binder failing with ErrEmptyBody when BindBody gets an empty body,
+// for the endpoints where the payload is mandatory.
+func (b *DefaultBinder) RequireBody(required bool) *DefaultBinder {
+	c := b.clone()
+	c.BodyRequired = required
+	return c
+}
+
 func (b *DefaultBinder) GetPathParams(r BindableRequest) map[string][]string {
 	pattern := r.GetPathPattern()
 	if pattern == "" {
 		return nil
 	}
 
-	names := b.PathMatcher.FindAllStringSubmatch(pattern, -1)
-	if len(names) == 0 {
+	specs := b.ParsePathPattern(pattern)
+	if len(specs) == 0 {
 		return nil
 	}
 
 	values := map[string][]string{}
-	for _, name := range names {
-		values[name[1]] = []string{r.GetPathValue(name[1])}
+	for _, spec := range specs {
+		values[spec.Name] = []string{r.GetPathValue(spec.Name)}
 	}
 	return values
 }
 
+// GetQueryParams returns the query params of the request after applying the QueryNormalizers.
 func (b *DefaultBinder) GetQueryParams(r BindableRequest) map[string][]string {
-	return r.GetQuery()
+	values := r.GetQuery()
+	for _, normalize := range b.QueryNormalizers {
+		values = normalize(values)
+	}
+	return values
+}
+
+// RegisterQueryNormalizer adds a function normalizing the raw query params before binding.
+func (b *DefaultBinder) RegisterQueryNormalizer(fn QueryNormalizer) {
+	b.QueryNormalizers = append(b.QueryNormalizers, fn)
+}
+
+// GetFormValues returns the urlencoded form values of the request, without the URL query values
+// when FormBodyOnly is enabled and the request implements PostFormRequest.
+func (b *DefaultBinder) GetFormValues(r BindableRequest) (url.Values, error) {
+	if pr, ok := findRequest[PostFormRequest](r); ok && b.FormBodyOnly {
+		return pr.GetPostForm()
+	}
+	return r.GetForm()
 }
 
 func (b *DefaultBinder) GetHeaders(r BindableRequest) map[string][]string {
@@ -83,7 +294,10 @@ func (b *DefaultBinder) GetHeaders(r BindableRequest) map[string][]string {
 // BindPathParams binds path params to bindable object
 func (b *DefaultBinder) BindPathParams(r BindableRequest, i interface{}) error {
 	values := b.GetPathParams(r)
-	if err := b.bindData(i, values, b.ParamTagName, nil); err != nil {
+	b.recordInput(SourcePath, values, nil)
+	ctx, release := b.bindContext(r)
+	defer release()
+	if err := b.bindData(ctx, i, values, b.ParamTagName, nil); err != nil {
 		return err
 	}
 	return nil
@@ -92,7 +306,13 @@ func (b *DefaultBinder) BindPathParams(r BindableRequest, i interface{}) error {
 // BindQueryParams binds query params to bindable object
 func (b *DefaultBinder) BindQueryParams(r BindableRequest, i interface{}) error {
 	values := b.GetQueryParams(r)
-	if err := b.bindData(i, values, b.QueryTagName, nil); err != nil {
+	b.recordInput(SourceQuery, values, nil)
+	ctx, release := b.bindContext(r)
+	defer release()
+	if err := b.checkUnknownKeys(i, values, nil, b.QueryTagName); err != nil {
+		return err
+	}
+	if err := b.bindData(ctx, i, values, b.QueryTagName, nil); err != nil {
 		return err
 	}
 	return nil
@@ -104,39 +324,90 @@ func (b *DefaultBinder) BindQueryParams(r BindableRequest, i interface{}) error
 // See non-MIMEMultipartForm: https://golang.org/pkg/net/http/#Request.ParseForm
 // See MIMEMultipartForm: https://golang.org/pkg/net/http/#Request.ParseMultipartForm
 func (b *DefaultBinder) BindBody(r BindableRequest, i interface{}) (err error) {
-	if r.GetContentLength() <= 0 {
+	mediatype, params := ParseMediaType(r.GetContentType())
+	// serializers can get the parsed media type and its params with GetMediaType
+	mr := &mediaTypeRequest{BindableRequest: r, mediaType: mediatype, params: params}
+	if err = b.limitBody(mr); err != nil {
+		return err
+	}
+	// the length of chunked bodies is unknown (-1), they are only empty when nothing can be read
+	if length := r.GetContentLength(); length == 0 || (length < 0 && mr.emptyBody()) {
+		if b.BodyRequired {
+			return ErrEmptyBody
+		}
+		if isFormMediaType(mediatype) {
+			// a form of unchecked checkboxes only is submitted empty
+			b.resetCheckboxes(reflect.ValueOf(i), b.FormTagName)
+		}
 		return
 	}
-	// return
+	ctx, release := b.bindContext(r)
+	defer release()
+	r = mr
+
+	setRawBody, err := b.captureRawBody(mr, i)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			setRawBody()
+		}
+	}()
+	if err = b.processBody(mr); err != nil {
+		return err
+	}
 
-	// mediatype is found like `mime.ParseMediaType()` does it
-	base, _, _ := strings.Cut(r.GetHeaders().Get(HeaderContentType), ";")
-	mediatype := strings.TrimSpace(base)
+	if deserializer, ok := b.Deserializers[mediatype]; ok {
+		return b.deserialize(deserializer, r, mediatype, params, i)
+	}
 
 	switch mediatype {
 	case MIMEApplicationJSON:
-		if err = b.JSONSerializer.Deserialize(r, i); err != nil {
+		if err = b.deserialize(b.JSONSerializer, r, mediatype, params, i); err != nil {
 			return err
 		}
 	case MIMEApplicationXML, MIMETextXML:
-		if err = b.XMLSerializer.Deserialize(r, i); err != nil {
+		if err = b.deserialize(b.XMLSerializer, r, mediatype, params, i); err != nil {
 			return err
 		}
+		if b.XMLParamOverlay {
+			if err = b.overlayXMLParams(ctx, r, i); err != nil {
+				return err
+			}
+		}
 	case MIMEApplicationForm:
 		var form url.Values
-		if form, err = r.GetForm(); err != nil {
-			return err
+		if form, err = b.GetFormValues(r); err != nil {
+			return bodyLengthError(r, err)
 		}
 
-		if err = b.bindData(i, form, b.FormTagName, nil); err != nil {
+		b.recordBody(r)
+		form = b.withoutReservedFields(form)
+		b.recordInput(SourceBody, form, nil)
+		if err = b.checkUnknownKeys(i, form, nil, b.FormTagName); err != nil {
+			return err
+		}
+		if err = b.bindData(ctx, i, form, b.FormTagName, nil); err != nil {
 			return err
 		}
 	case MIMEMultipartForm:
 		var params *multipart.Form
+		mr.checkMultipartLength()
 		if params, err = r.GetMultipartForm(b.MaxBodySize); err != nil {
+			return multipartError(r, err)
+		}
+		if params == nil {
+			// requests without any part
+			params = &multipart.Form{}
+		}
+		b.recordBody(r)
+		values := b.withoutReservedFields(params.Value)
+		b.recordInput(SourceBody, values, params.File)
+		if err = b.checkUnknownKeys(i, values, params.File, b.FormTagName); err != nil {
 			return err
 		}
-		if err = b.bindData(i, params.Value, b.FormTagName, params.File); err != nil {
+		if err = b.bindData(ctx, i, values, b.FormTagName, params.File); err != nil {
 			return err
 		}
 	default:
@@ -145,30 +416,317 @@ func (b *DefaultBinder) BindBody(r BindableRequest, i interface{}) (err error) {
 	return nil
 }
 
+// overlayXMLParams binds the path and query params onto an XML decoded struct with the xml tag names,
+// the query params taking precedence over the path params.
+func (b *DefaultBinder) overlayXMLParams(ctx context.Context, r BindableRequest, i interface{}) error {
+	if typ := reflect.TypeOf(i); typ == nil || typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	values := map[string][]string{}
+	for k, v := range b.GetPathParams(r) {
+		values[k] = v
+	}
+	for k, v := range b.GetQueryParams(r) {
+		values[k] = v
+	}
+	return b.bindData(ctx, i, values, b.XMLTagName, nil)
+}
+
+// xmlOwnsField reports whether the field is left to the XML decoder by the param overlay: xml.Name,
+// chardata, cdata, innerxml, comment and any fields, and types implementing xml.Unmarshaler or
+// xml.UnmarshalerAttr.
+func xmlOwnsField(field reflect.StructField, value reflect.Value) bool {
+	if field.Type == xmlNameType {
+		return true
+	}
+	// encoding/xml always reads the xml tag, whatever the XMLTagName of the binder
+	options := strings.Split(field.Tag.Get("xml"), ",")
+	for _, option := range options[1:] {
+		switch strings.TrimSpace(option) {
+		case "chardata", "cdata", "innerxml", "comment", "any":
+			return true
+		}
+	}
+	switch value.Addr().Interface().(type) {
+	case xml.Unmarshaler, xml.UnmarshalerAttr:
+		return true
+	}
+	return false
+}
+
+// deserialize decodes the body with the deserializer, see decode, recording its size in the report.
+func (b *DefaultBinder) deserialize(deserializer Deserializer, r BindableRequest, mediatype string, params map[string]string, i interface{}) error {
+	err := b.decode(deserializer, r, mediatype, params, i)
+	b.recordBody(r)
+	return err
+}
+
+// decode decodes the body of the request with the deserializer, passing the parsed media type to
+// MediaTypeDeserializer implementations, then applies the bind tag, the readonly, sources and maxbytes
+// options and the catch-all of the unknown JSON keys to the decoded fields.
+func (b *DefaultBinder) decode(deserializer Deserializer, r BindableRequest, mediatype string, params map[string]string, i interface{}) error {
+	// decoders know nothing about the bind tag and the readonly option, so these fields are restored after decoding
+	restoreExcluded := b.snapshotExcluded(i)
+	restoreReadOnly := snapshotFields(i, isReadOnly)
+	restoreSourced := b.snapshotSourced(i, bodySource(mediatype))
+	collectUnknown := captureUnknownJSON(r, mediatype, i)
+	markPresent := b.capturePresentJSON(r, mediatype, i)
+	var err error
+	if md, ok := deserializer.(MediaTypeDeserializer); ok {
+		err = md.DeserializeMediaType(r, mediatype, params, i)
+	} else {
+		err = deserializer.Deserialize(r, i)
+	}
+	restoreExcluded()
+	restoreSourced()
+	changed, supplied := restoreReadOnly()
+	if err != nil {
+		return bodyLengthError(r, err)
+	}
+	if err := b.readOnlyError(mediatype, bodyFieldName(mediatype, changed), supplied); err != nil {
+		return err
+	}
+	if err := collectUnknown(); err != nil {
+		return err
+	}
+	if err := b.limitDecodedStrings(i, mediatype); err != nil {
+		return err
+	}
+	markPresent()
+	// decoders may stop reading right after a complete value
+	if mr, ok := r.(*mediaTypeRequest); ok {
+		return mr.bodyLengthError()
+	}
+	return nil
+}
+
+// RegisterDeserializer registers the body deserializer of a media type, i.e. `application/vnd.acme.v2+json`.
+// Registered deserializers take precedence over the built-in ones.
+func (b *DefaultBinder) RegisterDeserializer(mediaType string, deserializer Deserializer) {
+	if b.Deserializers == nil {
+		b.Deserializers = map[string]Deserializer{}
+	}
+	b.Deserializers[strings.ToLower(mediaType)] = deserializer
+}
+
 // BindHeaders binds HTTP headers to a bindable object
+// Slice fields receive all the values of a repeated header.
 func (b *DefaultBinder) BindHeaders(r BindableRequest, i interface{}) error {
-	if err := b.bindData(i, r.GetHeaders(), b.FormTagName, nil); err != nil {
+	values := b.GetHeaders(r)
+	b.recordInput(SourceHeader, values, nil)
+	if err := b.checkHeaderLimits(values); err != nil {
+		return err
+	}
+	ctx, release := b.bindContext(r)
+	defer release()
+	if err := b.bindData(ctx, i, values, b.HeaderTagName, nil); err != nil {
 		return err
 	}
 	return nil
 }
 
+// checkHeaderLimits returns a *LimitError when the headers exceed MaxHeaderValues or MaxHeaderBytes.
+func (b *DefaultBinder) checkHeaderLimits(values map[string][]string) error {
+	count, size := 0, int64(0)
+	for key, v := range values {
+		count += len(v)
+		size += int64(len(key) * len(v))
+		for _, value := range v {
+			size += int64(len(value))
+		}
+	}
+	if b.MaxHeaderValues > 0 && count > b.MaxHeaderValues {
+		return &LimitError{Source: b.HeaderTagName, Limit: "values", Max: int64(b.MaxHeaderValues)}
+	}
+	if b.MaxHeaderBytes > 0 && size > b.MaxHeaderBytes {
+		return &LimitError{Source: b.HeaderTagName, Limit: "bytes", Max: b.MaxHeaderBytes}
+	}
+	return nil
+}
+
 // Bind implements the `Binder#Bind` function.
-// Binding is done in following order: 1) path params; 2) query params; 3) request body. Each step COULD override previous
+// Binding is done in following order: 1) request metadata; 2) path params; 3) query params; 4) request body. Each step COULD override previous
 // step binded values. For single source binding use their own methods BindBody, BindQueryParams, BindPathParams.
+// Destinations implementing Bindable bind themselves instead.
 func (b *DefaultBinder) Bind(r BindableRequest, i interface{}) (err error) {
-	for _, bindFunc := range b.BindOrder {
-		if err = bindFunc(r, i); err != nil {
+	return b.bindAll(r, i)
+}
+
+// bindAll binds the sources of BindOrder, or delegates to a Bindable destination between the BeforeBind and
+// AfterBind hooks.
+func (b *DefaultBinder) bindAll(r BindableRequest, i interface{}) error {
+	bindable, ok := i.(Bindable)
+	if !ok {
+		return b.bindSteps(r, i, b.BindOrder)
+	}
+	if b.DetectConcurrentBinds {
+		release, err := guardDestination(i)
+		if err != nil {
 			return err
 		}
+		defer release()
+	}
+	if err := runHooks(b.BeforeBind, r, i); err != nil {
+		return err
+	}
+	if err := bindable.BindRequest(r, b); err != nil {
+		return err
 	}
+	return runHooks(b.AfterBind, r, i)
+}
 
-	return nil
+// objectMatcher returns the matcher for struct and map keys in bracket notation: the DeepObjectMatcher
+// for query params (OpenAPI deepObject style, i.e. `filter[name]=x`), or the given form matcher.
+func (b *DefaultBinder) objectMatcher(tag string, matcher *regexp.Regexp) *regexp.Regexp {
+	if tag == b.QueryTagName && b.DeepObjectMatcher != nil {
+		return b.DeepObjectMatcher
+	}
+	return matcher
+}
+
+// checkUnknownKeys returns an *UnknownFieldError listing the keys that do not match any field
+// tagged with the given tag when StrictKeys is enabled. Map destinations accept any key.
+func (b *DefaultBinder) checkUnknownKeys(destination interface{}, data map[string][]string, dataFiles map[string][]*multipart.FileHeader, tag string) error {
+	if !b.StrictKeys || destination == nil {
+		return nil
+	}
+	typ := reflect.TypeOf(destination)
+	unknown := []string{}
+	for key := range data {
+		if !b.isKnownKey(typ, key, tag) {
+			unknown = append(unknown, key)
+		}
+	}
+	for key := range dataFiles {
+		if !b.isKnownKey(typ, key, tag) {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return &UnknownFieldError{Source: tag, Fields: unknown}
+}
+
+// isKnownKey reports whether the key (possibly in dot or bracket notation) can be bound to the type.
+func (b *DefaultBinder) isKnownKey(typ reflect.Type, key string, tag string) bool {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() == reflect.Map {
+		return true
+	}
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+
+	caseInsensitive := b.caseInsensitiveKeys(tag)
+	for i := 0; i < typ.NumField(); i++ {
+		typeField := typ.Field(i)
+		fieldType := typeField.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if b.isExcluded(typeField, tag) {
+			continue
+		}
+		inputFieldName := strings.TrimSuffix(b.fieldTagName(typeField, tag), "[]")
+		if inputFieldName == "" && b.usesFieldNameFallback(typeField, tag) && (fieldType.Kind() != reflect.Struct || b.bindsAsValue(reflect.New(fieldType).Elem())) {
+			if name := b.fallbackFieldName(typeField.Name, map[string][]string{key: nil}, nil); name != "" {
+				inputFieldName = name
+			}
+		}
+		if prefix, ok := strings.CutSuffix(inputFieldName, "*"); ok {
+			// captured by the map field
+			if hasKeyPrefix(key, prefix, caseInsensitive) {
+				return true
+			}
+			continue
+		}
+		if inputFieldName == "" {
+			if fieldType.Kind() == reflect.Struct && !reflect.PointerTo(fieldType).Implements(bindUnmarshalerType) && b.isKnownKey(fieldType, key, tag) {
+				return true
+			}
+			continue
+		}
+		if equalKeys(key, inputFieldName, caseInsensitive) || equalKeys(key, inputFieldName+"[]", caseInsensitive) {
+			return true
+		}
+
+		var rest string
+		if after, ok := cutKeyPrefix(key, inputFieldName+b.DeepObjectSeparator, caseInsensitive); ok {
+			rest = after
+		} else if after, ok := cutKeyPrefix(key, inputFieldName, caseInsensitive); ok && strings.HasPrefix(after, "[") {
+			parts := []string{}
+			for _, match := range b.objectMatcher(tag, b.ArrayNotationMatcher).FindAllStringSubmatch(after, -1) {
+				parts = append(parts, match[1])
+			}
+			rest = strings.Join(parts, b.DeepObjectSeparator)
+		} else {
+			continue
+		}
+
+		switch fieldType.Kind() {
+		case reflect.Map, reflect.Slice:
+			return true
+		case reflect.Struct:
+			if b.isKnownKey(fieldType, rest, tag) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // bindData will bind data ONLY fields in destination struct that have EXPLICIT tag
-func (b *DefaultBinder) bindData(destination interface{}, data map[string][]string, tag string, dataFiles map[string][]*multipart.FileHeader) error {
-	if destination == nil || (len(data) == 0 && len(dataFiles) == 0) {
+func (b *DefaultBinder) bindData(ctx context.Context, destination interface{}, data map[string][]string, tag string, dataFiles map[string][]*multipart.FileHeader) error {
+	return b.bindFields(ctx, destination, data, tag, dataFiles, nil)
+}
+
+// resetCheckboxes sets the checkbox fields of a struct without form data, and of its nested structs, to
+// false: browsers do not submit unchecked checkboxes, so the form holds no key for the struct, or is empty
+// when every box is unchecked. Nil structs are left alone.
+func (b *DefaultBinder) resetCheckboxes(val reflect.Value, tag string) {
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return
+	}
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		typeField := typ.Field(i)
+		field := val.Field(i)
+		if !field.CanSet() || b.isExcluded(typeField, tag) || isReadOnly(typeField) {
+			continue
+		}
+		switch {
+		case field.Kind() == reflect.Struct && !b.bindsAsValue(field),
+			field.Kind() == reflect.Ptr && typeField.Type.Elem().Kind() == reflect.Struct:
+			b.resetCheckboxes(field, tag)
+		case b.fieldTagName(typeField, tag) == "" || !(b.FormCheckboxes || hasTagFlag(typeField, tag, "checkbox")):
+		case field.Kind() == reflect.Bool:
+			field.SetBool(false)
+		case field.Kind() == reflect.Ptr && typeField.Type.Elem().Kind() == reflect.Bool:
+			field.Set(reflect.New(typeField.Type.Elem()))
+		}
+	}
+}
+
+// bindFields binds the data like bindData, skipping the shadowed fields of the embedded structs.
+// The shadowed fields are computed for the destination when nil, and passed to the flattened structs.
+func (b *DefaultBinder) bindFields(ctx context.Context, destination interface{}, data map[string][]string, tag string, dataFiles map[string][]*multipart.FileHeader, shadowed shadowSet) error {
+	if destination == nil {
+		return nil
+	}
+	if len(data) == 0 && len(dataFiles) == 0 {
+		if tag == b.FormTagName {
+			b.resetCheckboxes(reflect.ValueOf(destination), tag)
+		}
 		return nil
 	}
 	hasFiles := len(dataFiles) > 0
@@ -179,28 +737,79 @@ func (b *DefaultBinder) bindData(destination interface{}, data map[string][]stri
 	// - map[string][]string,
 	// - map[string]string <-- (binds first value from data slice)
 	// - map[string]interface{}
+	// - map[string]int, map[string]float64, map[string]bool... <-- (converts first value from data slice)
+	// - map[string][]int, map[string][]float64, map[string][]bool...
+	// - map[string]*multipart.FileHeader, map[string][]*multipart.FileHeader <-- (binds the files only)
+	// Keys other than strings (map[int]string, map[uuid.UUID]string...) are converted like field values.
+	// Files are also bound to map[string]interface{} as *multipart.FileHeader, or []*multipart.FileHeader
+	// when several files share the key.
 	// You are better off binding to struct but there are user who want this map feature. Source of data for these cases are:
 	// params,query,header,form as these sources produce string values, most of the time slice of strings, actually.
-	if typ.Kind() == reflect.Map && typ.Key().Kind() == reflect.String {
+	if typ.Kind() == reflect.Map {
 		k := typ.Elem().Kind()
 		isElemInterface := k == reflect.Interface
 		isElemString := k == reflect.String
 		isElemSliceOfStrings := k == reflect.Slice && typ.Elem().Elem().Kind() == reflect.String
-		if !(isElemSliceOfStrings || isElemString || isElemInterface) {
+		isElemScalar := isScalarKind(k)
+		isElemSliceOfScalars := k == reflect.Slice && isScalarKind(typ.Elem().Elem().Kind())
+		isElemFile := typ.Elem() == multipartFileHeaderPointerType || typ.Elem() == multipartFileHeaderPointerSliceType
+		if !(isElemSliceOfStrings || isElemString || isElemInterface || isElemScalar || isElemSliceOfScalars || isElemFile) {
 			return nil
 		}
 		if val.IsNil() {
 			val.Set(reflect.MakeMap(typ))
 		}
+		if isElemFile || isElemInterface {
+			for k, v := range dataFiles {
+				if len(v) == 0 {
+					continue
+				}
+				key, err := b.convertMapKey(k, typ.Key())
+				if err != nil {
+					return err
+				}
+				if typ.Elem() == multipartFileHeaderPointerSliceType || (isElemInterface && len(v) > 1) {
+					val.SetMapIndex(key, reflect.ValueOf(v))
+				} else {
+					val.SetMapIndex(key, reflect.ValueOf(v[0]))
+				}
+			}
+		}
+		if isElemFile {
+			return nil
+		}
 		for k, v := range data {
+			key, err := b.convertMapKey(k, typ.Key())
+			if err != nil {
+				return err
+			}
 			if isElemString {
-				val.SetMapIndex(reflect.ValueOf(k), reflect.ValueOf(v[0]))
+				val.SetMapIndex(key, reflect.ValueOf(v[0]).Convert(typ.Elem()))
 			} else if isElemInterface {
-				// To maintain backward compatibility, we always bind to the first string value
+				// To maintain backward compatibility, we bind to the first string value
 				// and not the slice of strings when dealing with map[string]interface{}{}
-				val.SetMapIndex(reflect.ValueOf(k), reflect.ValueOf(v[0]))
+				// unless MapMultiValues is enabled
+				if (b.MapMultiValues || b.compatLevel() >= CompatV2) && len(v) > 1 {
+					val.SetMapIndex(key, reflect.ValueOf(v))
+				} else {
+					val.SetMapIndex(key, reflect.ValueOf(v[0]))
+				}
+			} else if isElemScalar {
+				elem := reflect.New(typ.Elem()).Elem()
+				if err := b.setWithProperType(elem.Kind(), v[0], elem); err != nil {
+					return fmt.Errorf("%s: %w", k, err)
+				}
+				val.SetMapIndex(key, elem)
+			} else if isElemSliceOfScalars {
+				slice := reflect.MakeSlice(typ.Elem(), len(v), len(v))
+				for j := range v {
+					if err := b.setWithProperType(slice.Index(j).Kind(), v[j], slice.Index(j)); err != nil {
+						return fmt.Errorf("%s: %w", k, err)
+					}
+				}
+				val.SetMapIndex(key, slice)
 			} else {
-				val.SetMapIndex(reflect.ValueOf(k), reflect.ValueOf(v))
+				val.SetMapIndex(key, reflect.ValueOf(v).Convert(typ.Elem()))
 			}
 		}
 		return nil
@@ -220,7 +829,15 @@ func (b *DefaultBinder) bindData(destination interface{}, data map[string][]stri
 		typ = typ.Elem()
 		val = val.Elem()
 	}
+	if shadowed == nil {
+		var ambiguous []string
+		shadowed, ambiguous = b.shadowedFields(typ, tag)
+		if b.StrictAmbiguousFields && len(ambiguous) > 0 {
+			return &AmbiguousFieldError{Source: tag, Field: ambiguous[0]}
+		}
+	}
 
+	var envelopes []envelopeField
 	for i := 0; i < typ.NumField(); i++ { // iterate over all destination fields
 		typeField := typ.Field(i)
 		structField := val.Field(i)
@@ -230,21 +847,78 @@ func (b *DefaultBinder) bindData(destination interface{}, data map[string][]stri
 			}
 		}
 		if !structField.CanSet() {
+			if err := b.unsettableFieldError(typeField, tag); err != nil {
+				return err
+			}
+			continue
+		}
+		if isRawBodyField(typeField) {
+			continue
+		}
+		if b.isExcluded(typeField, tag) || shadowed[shadowKey{typ: typ, index: i}] {
 			continue
 		}
 		structFieldKind := structField.Kind()
-		inputFieldName := typeField.Tag.Get(tag)
+		// PHP-style append keys are matched with and without the brackets, i.e. `tags` and `tags[]`
+		inputFieldName := strings.TrimSuffix(b.fieldTagName(typeField, tag), "[]")
+		if tag == b.XMLTagName {
+			if xmlOwnsField(typeField, structField) {
+				continue
+			}
+			// xml paths are bound in dot notation, i.e. `address>city` from `address.city`
+			inputFieldName = strings.ReplaceAll(inputFieldName, ">", b.DeepObjectSeparator)
+		}
 
 		if typeField.Anonymous && structFieldKind == reflect.Struct && inputFieldName != "" {
 			// if anonymous struct with query/param/form tags, report an error
 			return errors.New("query/param/form tags are not allowed with anonymous struct field")
 		}
 
+		if inputFieldName == "" && b.usesFieldNameFallback(typeField, tag) && (structFieldKind != reflect.Struct || b.bindsAsValue(structField)) {
+			inputFieldName = b.fallbackFieldName(typeField.Name, data, dataFiles)
+		}
+
+		sources := b.fieldSources(typeField)
+		if sources != nil && inputFieldName == "" {
+			// the bind tag name applies to the sources without their own tag
+			inputFieldName = tagName(typeField, b.BindTagName)
+		}
+
+		if inputFieldName != "" && isReadOnly(typeField) {
+			if err := b.readOnlyError(tag, inputFieldName, suppliesInput(data, dataFiles, inputFieldName, b.DeepObjectSeparator, b.caseInsensitiveKeys(tag))); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if sources != nil && inputFieldName != "" {
+			rank := b.sourceRank(sources, tag)
+			if rank < 0 || !b.claimField(structField, rank, suppliesInput(data, dataFiles, inputFieldName, b.DeepObjectSeparator, b.caseInsensitiveKeys(tag))) {
+				continue
+			}
+		}
+
+		if inputFieldName != "" && isEnvelopeField(typeField, tag) {
+			envelopes = append(envelopes, envelopeField{field: structField, name: inputFieldName})
+			continue
+		}
+
+		if prefix, ok := strings.CutSuffix(inputFieldName, "*"); ok {
+			if err := b.captureKeys(structField, typeField, tag, prefix, data); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if b.present != nil && inputFieldName != "" && suppliesInput(data, dataFiles, inputFieldName, b.DeepObjectSeparator, b.caseInsensitiveKeys(tag)) {
+			b.markPresent(structField)
+		}
+
 		if inputFieldName == "" {
 			// If tag is nil, we inspect if the field is a not BindUnmarshaler struct and try to bind data into it (might contain fields with tags).
 			// structs that implement BindUnmarshaler are bound only when they have explicit tag
 			if _, ok := structField.Addr().Interface().(BindUnmarshaler); !ok && structFieldKind == reflect.Struct {
-				if err := b.bindData(structField.Addr().Interface(), data, tag, dataFiles); err != nil {
+				if err := b.bindFields(ctx, structField.Addr().Interface(), data, tag, dataFiles, shadowed); err != nil {
 					return err
 				}
 			}
@@ -263,59 +937,78 @@ func (b *DefaultBinder) bindData(destination interface{}, data map[string][]stri
 		}
 
 		//if the field is a struct, we need to recursively bind data to it
-		if structFieldKind == reflect.Struct {
+		// types with custom JSON behavior are bound as a value when the key itself is present
+		isJSONValue := isJSONUnmarshaler(structField) && hasInput(data, inputFieldName, b.caseInsensitiveKeys(tag))
+		if structFieldKind == reflect.Struct && !b.bindsAsValue(structField) && !isJSONValue {
 			// the data now is only the data that is relevant to the current struct
-			structData := trimData(inputFieldName, data, b.ArrayNotationMatcher, b.DeepObjectSeparator)
-			structFiles := trimFileFields(inputFieldName, dataFiles, b.ArrayNotationMatcher, b.DeepObjectSeparator)
-			if err := b.bindData(structField.Addr().Interface(), structData, tag, structFiles); err != nil {
+			structData := trimData(scratchFrom(ctx), inputFieldName, data, b.objectMatcher(tag, b.ArrayNotationMatcher), b.DeepObjectSeparator)
+			structFiles := trimFileFields(inputFieldName, dataFiles, b.objectMatcher(tag, b.ArrayNotationMatcher), b.DeepObjectSeparator)
+			if len(structData) == 0 && len(structFiles) == 0 && tag == b.FormTagName {
+				b.resetCheckboxes(structField, tag)
+				continue
+			}
+			if err := b.bindData(ctx, structField.Addr().Interface(), structData, tag, structFiles); err != nil {
 				return err
 			}
 			continue
 		} else if structFieldKind == reflect.Map {
 			// the data now is only the data that is relevant to the current field
-			mapData := trimData(inputFieldName, data, b.MapMatcher, b.DeepObjectSeparator)
-			mapFiles := trimFileFields(inputFieldName, dataFiles, b.MapMatcher, b.DeepObjectSeparator)
-			if err := b.bindData(structField.Addr().Interface(), mapData, tag, mapFiles); err != nil {
-				return err
+			mapData := trimData(scratchFrom(ctx), inputFieldName, data, b.objectMatcher(tag, b.MapMatcher), b.DeepObjectSeparator)
+			mapFiles := trimFileFields(inputFieldName, dataFiles, b.objectMatcher(tag, b.MapMatcher), b.DeepObjectSeparator)
+			if err := b.bindData(ctx, structField.Addr().Interface(), mapData, tag, mapFiles); err != nil {
+				return b.redactError(typeField, tag, inputFieldName, err)
 			}
 			// continue
 		} else if structFieldKind == reflect.Slice {
 			// the data now is only the data that is relevant to the current field
 
-			sliceData := trimData(inputFieldName, data, b.ArrayMatcher, b.DeepObjectSeparator)
+			sliceData := trimData(scratchFrom(ctx), inputFieldName, data, b.ArrayMatcher, b.DeepObjectSeparator)
 			sliceFiles := trimFileFields(inputFieldName, dataFiles, b.ArrayMatcher, b.DeepObjectSeparator)
-			if err := handleArrayValues(structField, structFieldKind, sliceData, sliceFiles, inputFieldName, b.MaxArraySize); err != nil {
+			if err := b.limitData(typeField, tag, inputFieldName, sliceData); err != nil {
 				return err
 			}
+			if err := b.handleArrayValues(ctx, typeField, structField, structFieldKind, sliceData, sliceFiles, inputFieldName, tag, b.MaxArraySize); err != nil {
+				return b.redactError(typeField, tag, inputFieldName, err)
+			}
 		}
 
-		inputValue, exists := data[inputFieldName]
-		if !exists {
-			// Go json.Unmarshal supports case-insensitive binding.  However the
-			// url params are bound case-sensitive which is inconsistent.  To
-			// fix this we must check all of the map values in a
-			// case-insensitive search.
-			for k, v := range data {
-
-				if strings.EqualFold(k, inputFieldName) {
-					inputValue = v
-					exists = true
-					break
-				}
+		inputKey, inputValue, exists := lookupAppendInput(data, inputFieldName, b.caseInsensitiveKeys(tag))
+		if exists {
+			inputValue = b.normalizeValues(typeField, tag, inputValue)
+			if delimiter := b.valuesDelimiter(typeField, tag); delimiter != "" {
+				inputValue = splitValues(inputValue, delimiter)
 			}
+			limited, err := b.limitValues(typeField, tag, inputFieldName, inputValue)
+			if err != nil {
+				return err
+			}
+			inputValue = limited
 		}
 
 		if !exists {
+			if tag == b.FormTagName && (b.FormCheckboxes || hasTagFlag(typeField, tag, "checkbox")) {
+				// browsers do not submit unchecked checkboxes
+				if structFieldKind == reflect.Bool {
+					structField.SetBool(false)
+					continue
+				} else if structFieldKind == reflect.Ptr && typeField.Type.Elem().Kind() == reflect.Bool {
+					structField.Set(reflect.New(typeField.Type.Elem()))
+					continue
+				}
+			}
 
 			if structFieldKind == reflect.Ptr { // if the field is a pointer, we need to check if it is a struct
 
 				elem := typeField.Type.Elem() // get the type of the pointer
 				valueKind := elem.Kind()
 				if valueKind == reflect.Struct {
-					structData := trimData(inputFieldName, data, b.ArrayNotationMatcher, b.DeepObjectSeparator)
-					structFiles := trimFileFields(inputFieldName, dataFiles, b.ArrayNotationMatcher, b.DeepObjectSeparator)
+					structData := trimData(scratchFrom(ctx), inputFieldName, data, b.objectMatcher(tag, b.ArrayNotationMatcher), b.DeepObjectSeparator)
+					structFiles := trimFileFields(inputFieldName, dataFiles, b.objectMatcher(tag, b.ArrayNotationMatcher), b.DeepObjectSeparator)
 
 					if len(structData) == 0 && len(structFiles) == 0 { // no data for this field
+						if tag == b.FormTagName {
+							b.resetCheckboxes(structField, tag)
+						}
 						continue
 					}
 
@@ -324,13 +1017,13 @@ func (b *DefaultBinder) bindData(destination interface{}, data map[string][]stri
 					}
 
 					// fmt.Println("structFiles", structFiles)
-					if err := b.bindData(structField.Addr().Interface(), structData, tag, structFiles); err != nil {
+					if err := b.bindData(ctx, structField.Addr().Interface(), structData, tag, structFiles); err != nil {
 						return err
 					}
 					continue
 				} else if valueKind == reflect.Slice {
 					// the data now is only the data that is relevant to the current field
-					sliceData := trimData(inputFieldName, data, b.ArrayMatcher, b.DeepObjectSeparator)
+					sliceData := trimData(scratchFrom(ctx), inputFieldName, data, b.ArrayMatcher, b.DeepObjectSeparator)
 					sliceFiles := trimFileFields(inputFieldName, dataFiles, b.ArrayMatcher, b.DeepObjectSeparator)
 
 					if len(sliceData) == 0 && len(sliceFiles) == 0 { // no data for this field
@@ -340,14 +1033,17 @@ func (b *DefaultBinder) bindData(destination interface{}, data map[string][]stri
 					if structField.IsNil() {
 						structField.Set(reflect.New(structField.Type().Elem()))
 					}
-
-					if err := handleArrayValues(structField, structFieldKind, sliceData, sliceFiles, inputFieldName, b.MaxArraySize); err != nil {
+					if err := b.limitData(typeField, tag, inputFieldName, sliceData); err != nil {
 						return err
 					}
+
+					if err := b.handleArrayValues(ctx, typeField, structField.Elem(), reflect.Slice, sliceData, sliceFiles, inputFieldName, tag, b.MaxArraySize); err != nil {
+						return b.redactError(typeField, tag, inputFieldName, err)
+					}
 				} else if valueKind == reflect.Map {
 					// the data now is only the data that is relevant to the current field
-					mapData := trimData(inputFieldName, data, b.MapMatcher, b.DeepObjectSeparator)
-					mapFiles := trimFileFields(inputFieldName, dataFiles, b.MapMatcher, b.DeepObjectSeparator)
+					mapData := trimData(scratchFrom(ctx), inputFieldName, data, b.objectMatcher(tag, b.MapMatcher), b.DeepObjectSeparator)
+					mapFiles := trimFileFields(inputFieldName, dataFiles, b.objectMatcher(tag, b.MapMatcher), b.DeepObjectSeparator)
 
 					if len(mapData) == 0 && len(mapFiles) == 0 { // no data for this field
 						continue
@@ -356,55 +1052,105 @@ func (b *DefaultBinder) bindData(destination interface{}, data map[string][]stri
 						structField.Set(reflect.New(structField.Type().Elem()))
 					}
 
-					if err := b.bindData(structField.Interface(), mapData, tag, mapFiles); err != nil {
-						return err
+					if err := b.bindData(ctx, structField.Interface(), mapData, tag, mapFiles); err != nil {
+						return b.redactError(typeField, tag, inputFieldName, err)
 					}
 				}
 			}
 			continue
 		}
 
-		// NOTE: algorithm here is not particularly sophisticated. It probably does not work with absurd types like `**[]*int`
-		// but it is smart enough to handle niche cases like `*int`,`*[]string`,`[]*int` .
+		if err := b.setFieldValues(ctx, structField, typeField, tag, inputFieldName, inputKey, inputValue); err != nil {
+			return b.redactError(typeField, tag, inputFieldName, err)
+		}
+	}
+	return b.bindEnvelopes(ctx, envelopes, data, tag)
+}
 
-		// try unmarshalling first, in case we're dealing with an alias to an array type
-		if ok, err := unmarshalInputsToField(typeField.Type.Kind(), inputValue, structField); ok {
-			if err != nil {
-				return err
-			}
-			continue
+// setFieldValues converts the input values of a field, or into the value of an Optional field, trying in order
+// its named converter, the type converters, enums, the param unmarshalers, sql.Scanner and the builtin kinds.
+// The name of the input prefixes the conversion errors, and its key as found in the request is passed to
+// BindKeyUnmarshaler implementations.
+func (b *DefaultBinder) setFieldValues(ctx context.Context, structField reflect.Value, typeField reflect.StructField, tag string, inputFieldName string, inputKey string, inputValue []string) error {
+	if ok, err := b.setOptionalValues(ctx, structField, typeField, tag, inputFieldName, inputKey, inputValue); ok {
+		return err
+	}
+
+	structFieldKind := structField.Kind()
+	if name := b.converterName(typeField, tag); name != "" {
+		if err := b.convert(name, inputValue, structField, typeField); err != nil {
+			return fmt.Errorf("%s: %w", inputFieldName, err)
 		}
+		return nil
+	}
 
-		if ok, err := unmarshalInputToField(typeField.Type.Kind(), inputValue[0], structField); ok {
-			if err != nil {
-				return err
-			}
-			continue
+	if ok, err := b.convertType(inputValue, structField, typeField); ok {
+		if err != nil {
+			return fmt.Errorf("%s: %w", inputFieldName, err)
 		}
+		return nil
+	}
 
-		// we could be dealing with pointer to slice `*[]string` so dereference it. There are wierd OpenAPI generators
-		// that could create struct fields like that.
-		if structFieldKind == reflect.Pointer {
-			structFieldKind = structField.Elem().Kind()
-			structField = structField.Elem()
+	// NOTE: algorithm here is not particularly sophisticated. It probably does not work with absurd types like `**[]*int`
+	// but it is smart enough to handle niche cases like `*int`,`*[]string`,`[]*int` .
+
+	if ok, err := unmarshalEnumToField(typeField.Type.Kind(), inputValue[0], structField); ok {
+		if err != nil {
+			return fmt.Errorf("%s: %w", inputFieldName, err)
 		}
+		return nil
+	}
 
-		if structFieldKind == reflect.Slice {
-			sliceOf := structField.Type().Elem().Kind()
-			numElems := len(inputValue)
-			slice := reflect.MakeSlice(structField.Type(), numElems, numElems)
-			for j := 0; j < numElems; j++ {
-				if err := setWithProperType(sliceOf, inputValue[j], slice.Index(j)); err != nil {
-					return err
-				}
-			}
-			structField.Set(slice)
-			continue
+	if ok, err := unmarshalKeyInputsToField(typeField.Type.Kind(), inputKey, inputValue, structField); ok {
+		return err
+	}
+
+	if ok, err := unmarshalContextInputToField(ctx, typeField.Type.Kind(), inputValue[0], structField); ok {
+		return err
+	}
+
+	// try unmarshalling first, in case we're dealing with an alias to an array type
+	if ok, err := unmarshalInputsToField(typeField.Type.Kind(), inputValue, structField); ok {
+		return err
+	}
+
+	if ok, err := unmarshalInputToField(typeField.Type.Kind(), inputValue[0], structField); ok {
+		return err
+	}
+
+	if ok, err := unmarshalJSONInputToField(typeField.Type.Kind(), inputValue[0], structField); ok {
+		if err != nil {
+			return fmt.Errorf("%s: %w", inputFieldName, err)
 		}
+		return nil
+	}
 
-		if err := setWithProperType(structFieldKind, inputValue[0], structField); err != nil {
-			return err
+	if ok, err := scanInputToField(typeField.Type.Kind(), inputValue[0], structField); ok {
+		if err != nil {
+			return fmt.Errorf("%s: %w", inputFieldName, err)
 		}
+		return nil
 	}
-	return nil
+
+	// we could be dealing with pointer to slice `*[]string` so dereference it. There are wierd OpenAPI generators
+	// that could create struct fields like that.
+	if structFieldKind == reflect.Pointer {
+		structFieldKind = structField.Elem().Kind()
+		structField = structField.Elem()
+	}
+
+	if structFieldKind == reflect.Slice {
+		sliceOf := structField.Type().Elem().Kind()
+		numElems := len(inputValue)
+		slice := reflect.MakeSlice(structField.Type(), numElems, numElems)
+		for j := 0; j < numElems; j++ {
+			if err := b.setWithProperType(sliceOf, inputValue[j], slice.Index(j)); err != nil {
+				return unsupportedFieldType(err, inputFieldName)
+			}
+		}
+		structField.Set(slice)
+		return nil
+	}
+
+	return unsupportedFieldType(b.setWithProperType(structFieldKind, inputValue[0], structField), inputFieldName)
 }
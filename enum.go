@@ -0,0 +1,77 @@
+package binder
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// BindEnum is implemented by enum types to map the allowed param values to their constants.
+type BindEnum interface {
+	BindEnum() map[string]interface{}
+}
+
+var bindEnumType = reflect.TypeOf((*BindEnum)(nil)).Elem()
+
+// EnumError is returned when a value is not one of the values allowed by an enum type.
+type EnumError struct {
+	Value   string
+	Allowed []string
+}
+
+func (e *EnumError) Error() string {
+	return fmt.Sprintf("invalid value %q, allowed values are: %s", e.Value, strings.Join(e.Allowed, ", "))
+}
+
+// RegisterEnum registers the allowed values of an enum type, mapping each param value to its constant.
+func (b *DefaultBinder) RegisterEnum(typ reflect.Type, values map[string]interface{}) {
+	b.RegisterTypeConverter(typ, enumConverter(values))
+}
+
+// enumConverter returns a converter mapping the values to the constants of an enum.
+func enumConverter(values map[string]interface{}) ConverterFunc {
+	return func(input []string, dst reflect.Value, _ reflect.StructField) error {
+		if len(input) == 0 {
+			return nil
+		}
+		return setEnum(values, input[0], dst)
+	}
+}
+
+// setEnum sets the constant mapped to the value, or returns an *EnumError.
+func setEnum(values map[string]interface{}, value string, dst reflect.Value) error {
+	constant, ok := values[value]
+	if !ok {
+		allowed := make([]string, 0, len(values))
+		for k := range values {
+			allowed = append(allowed, k)
+		}
+		sort.Strings(allowed)
+		return &EnumError{Value: value, Allowed: allowed}
+	}
+	v := reflect.ValueOf(constant)
+	if !v.Type().ConvertibleTo(dst.Type()) {
+		return fmt.Errorf("enum value %v cannot be converted to %s", constant, dst.Type())
+	}
+	dst.Set(v.Convert(dst.Type()))
+	return nil
+}
+
+// unmarshalEnumToField binds the value to types implementing BindEnum.
+func unmarshalEnumToField(valueKind reflect.Kind, val string, field reflect.Value) (bool, error) {
+	typ := field.Type()
+	if valueKind == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if !typ.Implements(bindEnumType) {
+		return false, nil
+	}
+	if valueKind == reflect.Ptr {
+		if field.IsNil() {
+			field.Set(reflect.New(typ))
+		}
+		field = field.Elem()
+	}
+	return true, setEnum(field.Interface().(BindEnum).BindEnum(), val, field)
+}
@@ -0,0 +1,52 @@
+package binder
+
+import "reflect"
+
+// EnvLookup returns the value of a key of the environment, or of a configuration store, and whether it is
+// set, i.e. os.LookupEnv. Its values are bound with the env tag, see BindEnv.
+type EnvLookup func(key string) (string, bool)
+
+// GetEnvValues returns the values of the Env lookup for the env tags of the struct fields, and of its embedded
+// structs, or nil when the binder has no Env lookup.
+func (b *DefaultBinder) GetEnvValues(typ reflect.Type) map[string][]string {
+	if b.Env == nil {
+		return nil
+	}
+	values := map[string][]string{}
+	b.lookupEnvValues(typ, values)
+	return values
+}
+
+// lookupEnvValues adds the values of the env tags of the struct fields to values.
+func (b *DefaultBinder) lookupEnvValues(typ reflect.Type, values map[string][]string) {
+	for index := 0; index < typ.NumField(); index++ {
+		typeField := typ.Field(index)
+		if typeField.Anonymous && typeField.Type.Kind() == reflect.Struct {
+			b.lookupEnvValues(typeField.Type, values)
+			continue
+		}
+		name := tagName(typeField, b.EnvTagName)
+		if name == "" || name == "-" {
+			continue
+		}
+		if value, ok := b.Env(name); ok {
+			values[name] = []string{value}
+		}
+	}
+}
+
+// BindEnv binds the values of the Env lookup to fields with the env tag, i.e. `env:"FEATURE_SEARCH"`.
+// It comes first in BindOrder, so the env values are fallbacks set only when no request source supplies
+// a value, whatever the MergePolicy. Only struct destinations are bound, and only when the binder has an Env lookup.
+func (b *DefaultBinder) BindEnv(r BindableRequest, i interface{}) error {
+	typ := reflect.TypeOf(i)
+	if b.Env == nil || typ == nil || typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	ctx, release := b.bindContext(r)
+	defer release()
+	if err := b.bindData(ctx, i, b.GetEnvValues(typ.Elem()), b.EnvTagName, nil); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package binder
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// envelopeField is a field decoded from the JSON value of a single input, i.e. `form:"payload,json"`.
+type envelopeField struct {
+	field reflect.Value
+	name  string
+}
+
+// isEnvelopeField reports whether the field is decoded from the JSON value of its input with the `,json` option.
+func isEnvelopeField(field reflect.StructField, tag string) bool {
+	return hasTagFlag(field, tag, "json")
+}
+
+// bindEnvelopes decodes the JSON value of the inputs into their fields, once the other fields of the struct,
+// like the signature of the payload, are bound. The values are decoded like JSON bodies, with the JSONSerializer
+// of the binder and the options of the fields of the payload (bind:"-", readonly, maxbytes...).
+func (b *DefaultBinder) bindEnvelopes(ctx context.Context, envelopes []envelopeField, data map[string][]string, tag string) error {
+	for _, envelope := range envelopes {
+		_, values, ok := lookupInput(data, envelope.name, b.caseInsensitiveKeys(tag))
+		if !ok || len(values) == 0 || values[0] == "" {
+			continue
+		}
+		b.markPresent(envelope.field)
+		r := &mediaTypeRequest{BindableRequest: &envelopeRequest{ctx: ctx, value: values[0]}, mediaType: MIMEApplicationJSON}
+		if err := b.decode(b.JSONSerializer, r, MIMEApplicationJSON, map[string]string{}, envelope.field.Addr().Interface()); err != nil {
+			return fmt.Errorf("%s: %w", envelope.name, err)
+		}
+	}
+	return nil
+}
+
+// envelopeRequest is the request decoding the JSON value of an envelope field like a JSON body.
+type envelopeRequest struct {
+	ctx   context.Context
+	value string
+}
+
+func (r *envelopeRequest) GetBody() io.Reader {
+	return strings.NewReader(r.value)
+}
+
+func (r *envelopeRequest) GetPathPattern() string {
+	return ""
+}
+
+func (r *envelopeRequest) GetPathValue(string) string {
+	return ""
+}
+
+func (r *envelopeRequest) GetQuery() url.Values {
+	return url.Values{}
+}
+
+func (r *envelopeRequest) GetHeaders() url.Values {
+	return url.Values{"Content-Type": {MIMEApplicationJSON}}
+}
+
+func (r *envelopeRequest) GetContentLength() int64 {
+	return int64(len(r.value))
+}
+
+func (r *envelopeRequest) GetContentType() string {
+	return MIMEApplicationJSON
+}
+
+func (r *envelopeRequest) GetForm() (url.Values, error) {
+	return url.Values{}, nil
+}
+
+func (r *envelopeRequest) GetMultipartForm(int64) (*multipart.Form, error) {
+	return nil, nil
+}
+
+func (r *envelopeRequest) GetProto() string {
+	return ""
+}
+
+func (r *envelopeRequest) GetRemoteAddr() string {
+	return ""
+}
+
+func (r *envelopeRequest) Context() context.Context {
+	return r.ctx
+}
@@ -0,0 +1,190 @@
+package binder
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	// ErrFloatExponent is returned when a float value uses scientific notation and the binder does not allow it
+	ErrFloatExponent = errors.New("float values in scientific notation are not allowed")
+	// ErrFloatNonFinite is returned when a float value is Inf or NaN and the binder does not allow it
+	ErrFloatNonFinite = errors.New("non-finite float values are not allowed")
+	// ErrInvalidID is returned by the id converter when the value is not a positive integer
+	ErrInvalidID = errors.New("id must be a positive integer")
+	// ErrInvalidIP is returned when an IP address field receives an invalid address
+	ErrInvalidIP = errors.New("invalid ip address")
+	// ErrInvalidCIDR is returned when a network field receives an invalid CIDR
+	ErrInvalidCIDR = errors.New("invalid cidr")
+	// ErrRelativeURL is the parse error of a url.URL field receiving a URL without scheme
+	ErrRelativeURL = errors.New("url is not absolute")
+	// ErrEmptyBody is returned by BindBody when the body is empty and the binder requires it
+	ErrEmptyBody = errors.New("request body is required")
+	// ErrBodyTooLarge is matched by the *BodySizeError of a body exceeding MaxBodySize, and returned by BindBody
+	// when a multipart body has too many parts or exceeds the limit of an http.MaxBytesReader
+	ErrBodyTooLarge = errors.New("request body exceeds the max body size")
+	// ErrMalformedMultipart is returned by BindBody when a multipart body cannot be parsed, i.e. a missing
+	// or wrong boundary
+	ErrMalformedMultipart = errors.New("malformed multipart body")
+	// ErrSparseArray is returned with SparseArrayError when indexed notation skips indices
+	ErrSparseArray = errors.New("sparse array indices are not allowed")
+	// ErrUnknownSource is returned by BindSelected when a source name is not registered
+	ErrUnknownSource = errors.New("unknown bind source")
+	// ErrConcurrentBind is returned when DetectConcurrentBinds is enabled and the destination is already being bound
+	ErrConcurrentBind = errors.New("destination is already being bound by another goroutine")
+	// ErrUnsupportedFieldType is matched by the *UnsupportedFieldTypeError of a field no converter can set
+	ErrUnsupportedFieldType = errors.New("unsupported field type")
+)
+
+// ParseError is returned when a url.URL or mail.Address field receives a malformed value.
+type ParseError struct {
+	Type  reflect.Type // type of the field, without pointer
+	Value string       // value as found in the request
+	Err   error        // error of the parser
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid %s %q: %v", e.Type, e.Value, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// UnknownFieldError is returned in strict mode when the request contains keys
+// that do not match any field of the destination.
+type UnknownFieldError struct {
+	Source string   // source of the keys, i.e. json, query or form
+	Fields []string // unknown keys as found in the request
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("unknown %s field(s): %s", e.Source, strings.Join(e.Fields, ", "))
+}
+
+// UnsettableFieldError is returned with StrictFields when a field tagged for a source cannot be set,
+// because it is unexported or promoted from an unexported embedded struct, instead of being silently skipped.
+type UnsettableFieldError struct {
+	Source string // source the field is tagged for, i.e. query
+	Field  string // name of the struct field
+	Reason string // why the field cannot be set, i.e. unexported
+}
+
+func (e *UnsettableFieldError) Error() string {
+	return fmt.Sprintf("%s field %s cannot be set: %s", e.Source, e.Field, e.Reason)
+}
+
+// AmbiguousFieldError is returned with StrictAmbiguousFields when several embedded structs declare the same
+// input name at the same depth, so none of them is bound.
+type AmbiguousFieldError struct {
+	Source string // source the fields are tagged for, i.e. query
+	Field  string // input name declared by the fields, in lower case
+}
+
+func (e *AmbiguousFieldError) Error() string {
+	return fmt.Sprintf("ambiguous %s field %s declared by several embedded structs", e.Source, e.Field)
+}
+
+// LimitError is returned when a source exceeds one of the limits of the binder.
+type LimitError struct {
+	Source string // source of the values, i.e. header
+	Limit  string // exceeded limit, values or bytes
+	Max    int64  // configured maximum
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s exceeds the maximum of %d %s", e.Source, e.Max, e.Limit)
+}
+
+// MapKeyError is returned when a key cannot be converted to the key type of a map destination,
+// i.e. `?scores[abc]=1` for a map[int]int.
+type MapKeyError struct {
+	Key  string       // key as found in the request
+	Type reflect.Type // key type of the map
+	Err  error        // conversion error
+}
+
+func (e *MapKeyError) Error() string {
+	return fmt.Sprintf("invalid map key %q for %s: %v", e.Key, e.Type, e.Err)
+}
+
+func (e *MapKeyError) Unwrap() error {
+	return e.Err
+}
+
+// MapValueError is returned when the value of a key cannot be decoded into the element type of a map
+// destination, i.e. `{"size":1}` for the map[string]string catch-all of the unknown JSON keys.
+type MapValueError struct {
+	Key  string       // key as found in the request
+	Type reflect.Type // element type of the map
+	Err  error        // decode error
+}
+
+func (e *MapValueError) Error() string {
+	return fmt.Sprintf("invalid map value for key %q as %s: %v", e.Key, e.Type, e.Err)
+}
+
+func (e *MapValueError) Unwrap() error {
+	return e.Err
+}
+
+// UnsupportedFieldTypeError is returned when a field receives a value but its type has no converter,
+// i.e. a complex128 or a chan, telling which type to register a converter for with RegisterTypeConverter.
+type UnsupportedFieldTypeError struct {
+	Field string       // key of the field, as declared by the tag
+	Type  reflect.Type // type of the value that could not be set
+}
+
+func (e *UnsupportedFieldTypeError) Error() string {
+	return fmt.Sprintf("%s: unsupported field type %s, register a converter for it", e.Field, e.Type)
+}
+
+func (e *UnsupportedFieldTypeError) Unwrap() error {
+	return ErrUnsupportedFieldType
+}
+
+// FieldSizeError is returned with StringSizeError when a string value exceeds the `maxbytes` of its field.
+type FieldSizeError struct {
+	Source string // source of the value, i.e. query, form or the media type of the body
+	Field  string // key as declared by the tag of the source, i.e. the json tag for JSON bodies
+	Max    int    // max number of bytes of the field
+}
+
+func (e *FieldSizeError) Error() string {
+	return fmt.Sprintf("%s field %s exceeds the maximum of %d bytes", e.Source, e.Field, e.Max)
+}
+
+// MergeConflictError is returned with MergeErrorOnConflict when two sources of a Bind set different values to a field.
+type MergeConflictError struct {
+	Field    string // path of the struct field, i.e. `Address.City`
+	Previous string // source that set the field first, i.e. query
+	Source   string // source setting a different value, i.e. body
+}
+
+func (e *MergeConflictError) Error() string {
+	return fmt.Sprintf("field %s is set by both %s and %s with different values", e.Field, e.Previous, e.Source)
+}
+
+// SensitiveFieldError is returned instead of the error of a field tagged `sensitive:"true"`, i.e. a conversion
+// error quoting the value. It does not wrap the original error, so no error of the chain holds the value.
+type SensitiveFieldError struct {
+	Source string       // source of the value, i.e. query
+	Field  string       // key as declared by the tag of the source
+	Kind   reflect.Kind // kind of the field, i.e. int
+}
+
+func (e *SensitiveFieldError) Error() string {
+	return fmt.Sprintf("invalid %s value %s for %s field %s", e.Kind, RedactedValue, e.Source, e.Field)
+}
+
+// ReadOnlyFieldError is returned with ReadOnlyError when the request supplies a value for a `,readonly` field.
+type ReadOnlyFieldError struct {
+	Source string // source of the value, i.e. query, form or the media type of the body
+	Field  string // key as declared by the tag of the source, i.e. the json tag for JSON bodies
+}
+
+func (e *ReadOnlyFieldError) Error() string {
+	return fmt.Sprintf("%s field %s is read-only", e.Source, e.Field)
+}
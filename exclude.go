@@ -0,0 +1,147 @@
+package binder
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// isExcluded reports whether the field must never be bound from the source: its source tag or
+// the bind tag is `-`, i.e. `query:"-"` or `bind:"-"`.
+func (b *DefaultBinder) isExcluded(field reflect.StructField, tag string) bool {
+	if b.BindTagName != "" && field.Tag.Get(b.BindTagName) == "-" {
+		return true
+	}
+	return tagName(field, tag) == "-"
+}
+
+// snapshotExcluded saves the fields tagged with `bind:"-"` of the struct destination, see snapshotFields.
+func (b *DefaultBinder) snapshotExcluded(i interface{}) func() {
+	if b.BindTagName == "" {
+		return func() {}
+	}
+	restore := snapshotFields(i, func(field reflect.StructField) bool {
+		return field.Tag.Get(b.BindTagName) == "-"
+	})
+	return func() { restore() }
+}
+
+// snapshotFields saves the fields matching the predicate of the struct destination, its nested structs,
+// slice elements and map values, returning a function restoring them after decoding. Matching fields of the
+// structs allocated by the decoder are reset to their zero value. The function returns the first field whose
+// value was changed by the decoder, if any.
+func snapshotFields(i interface{}, match func(field reflect.StructField) bool) func() (reflect.StructField, bool) {
+	val := reflect.ValueOf(i)
+	if val.Kind() != reflect.Ptr || val.IsNil() || !hasMatchingFields(val.Type(), match, map[reflect.Type]bool{}) {
+		return func() (reflect.StructField, bool) { return reflect.StructField{}, false }
+	}
+	saved := map[string]reflect.Value{}
+	walkFields(val.Elem(), "", match, func(path string, field reflect.Value, _ reflect.StructField) {
+		value := reflect.New(field.Type()).Elem()
+		value.Set(field)
+		saved[path] = value
+	})
+	return func() (reflect.StructField, bool) {
+		var changed reflect.StructField
+		found := false
+		walkFields(val.Elem(), "", match, func(path string, field reflect.Value, typeField reflect.StructField) {
+			value, ok := saved[path]
+			if !ok {
+				value = reflect.Zero(field.Type())
+			}
+			if !found && !reflect.DeepEqual(field.Interface(), value.Interface()) {
+				changed, found = typeField, true
+			}
+			field.Set(value)
+		})
+		return changed, found
+	}
+}
+
+// walkFields calls visit with the settable fields matching the predicate reachable from the value, and
+// their path from the walked value. Map values, and structs held by interfaces, are not addressable: their
+// fields are visited on a copy set back in place.
+func walkFields(val reflect.Value, path string, match func(field reflect.StructField) bool, visit func(path string, field reflect.Value, typeField reflect.StructField)) {
+	switch val.Kind() {
+	case reflect.Ptr:
+		if !val.IsNil() {
+			walkFields(val.Elem(), path, match, visit)
+		}
+	case reflect.Interface:
+		if val.IsNil() {
+			return
+		}
+		switch elem := val.Elem(); {
+		case elem.Kind() == reflect.Ptr:
+			walkFields(elem, path, match, visit)
+		case elem.Kind() == reflect.Struct && val.CanSet():
+			value := reflect.New(elem.Type()).Elem()
+			value.Set(elem)
+			walkFields(value, path, match, visit)
+			val.Set(value)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			walkFields(val.Index(i), fmt.Sprintf("%s[%d]", path, i), match, visit)
+		}
+	case reflect.Map:
+		if val.IsNil() || !hasMatchingFields(val.Type().Elem(), match, map[reflect.Type]bool{}) {
+			return
+		}
+		iter := val.MapRange()
+		for iter.Next() {
+			keyPath := fmt.Sprintf("%s[%v]", path, iter.Key().Interface())
+			value := iter.Value()
+			if value.Kind() == reflect.Interface {
+				if value.IsNil() || (value.Elem().Kind() != reflect.Ptr && value.Elem().Kind() != reflect.Struct) {
+					continue
+				}
+				value = value.Elem()
+			}
+			if value.Kind() != reflect.Struct && value.Kind() != reflect.Array {
+				walkFields(value, keyPath, match, visit)
+				continue
+			}
+			elem := reflect.New(value.Type()).Elem()
+			elem.Set(value)
+			walkFields(elem, keyPath, match, visit)
+			val.SetMapIndex(iter.Key(), elem)
+		}
+	case reflect.Struct:
+		typ := val.Type()
+		for i := 0; i < typ.NumField(); i++ {
+			field := val.Field(i)
+			if !field.CanSet() {
+				continue
+			}
+			fieldPath := path + "." + typ.Field(i).Name
+			if match(typ.Field(i)) {
+				visit(fieldPath, field, typ.Field(i))
+				continue
+			}
+			walkFields(field, fieldPath, match, visit)
+		}
+	}
+}
+
+// hasMatchingFields reports whether the type has fields matching the predicate, to skip the walks.
+func hasMatchingFields(typ reflect.Type, match func(field reflect.StructField) bool, seen map[reflect.Type]bool) bool {
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return hasMatchingFields(typ.Elem(), match, seen)
+	case reflect.Interface:
+		// the value held is only known when walking
+		return true
+	case reflect.Struct:
+		if seen[typ] {
+			return false
+		}
+		seen[typ] = true
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if match(field) || hasMatchingFields(field.Type, match, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package binder
+
+import (
+	"mime/multipart"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// UploadedFile is a file uploaded under a form field, with its position among the files of the field
+// in upload order, so validators can report the offending part of a multi-file upload.
+// Bind it as `UploadedFile`, `*UploadedFile`, `[]UploadedFile` or `[]*UploadedFile`.
+type UploadedFile struct {
+	*multipart.FileHeader
+	Field string // form field of the file, i.e. `documents`
+	Index int    // position among the files of the field, starting at 0
+}
+
+// fieldFileHeaders returns the files uploaded under the field name in a stable order: the files of the
+// field name and of its append key (`documents[]`) in upload order, then the files of the indexed keys
+// (`documents[0]`) in index order.
+func fieldFileHeaders(name string, files map[string][]*multipart.FileHeader) []*multipart.FileHeader {
+	headers := append([]*multipart.FileHeader{}, files[name]...)
+	headers = append(headers, files[name+"[]"]...)
+
+	indexed := map[int][]*multipart.FileHeader{}
+	for k, v := range files {
+		rest, ok := strings.CutPrefix(k, name+"[")
+		if !ok {
+			continue
+		}
+		rest, ok = strings.CutSuffix(rest, "]")
+		if !ok {
+			continue
+		}
+		if index, err := strconv.Atoi(rest); err == nil && index >= 0 {
+			indexed[index] = v
+		}
+	}
+	indices := make([]int, 0, len(indexed))
+	for index := range indexed {
+		indices = append(indices, index)
+	}
+	sort.Ints(indices)
+	for _, index := range indices {
+		headers = append(headers, indexed[index]...)
+	}
+	return headers
+}
+
+// uploadedFiles returns the file headers of the field as uploaded files.
+func uploadedFiles(name string, headers []*multipart.FileHeader) []UploadedFile {
+	result := make([]UploadedFile, len(headers))
+	for i, header := range headers {
+		result[i] = UploadedFile{FileHeader: header, Field: name, Index: i}
+	}
+	return result
+}
@@ -0,0 +1,24 @@
+package binder
+
+// OnBeforeBind registers a hook run before the sources of every Bind and BindSelected, i.e. to scope the
+// destination to the tenant of the request. An error of the hook stops the bind.
+// Register the hooks before sharing the binder, or on a copy with With.
+func (b *DefaultBinder) OnBeforeBind(fn BindFunc) {
+	b.BeforeBind = append(b.BeforeBind, fn)
+}
+
+// OnAfterBind registers a hook run after every successful Bind and BindSelected, i.e. to default the
+// fields left empty or audit the bound values. An error of the hook is returned by the bind.
+func (b *DefaultBinder) OnAfterBind(fn BindFunc) {
+	b.AfterBind = append(b.AfterBind, fn)
+}
+
+// runHooks runs the hooks in their registration order, stopping at the first error.
+func runHooks(hooks []BindFunc, r BindableRequest, i interface{}) error {
+	for _, hook := range hooks {
+		if err := hook(r, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -7,12 +7,35 @@ import (
 	"net/url"
 )
 
+// DefaultHttpBinder is the binder of the BindHttp functions, wrapping the default binder.
+// Assigning it is only safe before serving requests; use SetDefaultBinder otherwise.
 var DefaultHttpBinder *HttpBinder
 
 type HttpBindableRequest struct {
 	*http.Request
 }
 
+// HTTPRequester is implemented by the bindable requests of an http.Request, like HttpBindableRequest and the
+// requests of the adapters embedding it. The binder reads and replaces the body of their http.Request, i.e. to
+// capture the raw body, to run the body processors or to enforce MaxBodySize before the forms are parsed.
+type HTTPRequester interface {
+	HTTPRequest() *http.Request
+}
+
+// HTTPRequest returns the wrapped http.Request.
+func (r HttpBindableRequest) HTTPRequest() *http.Request {
+	return r.Request
+}
+
+// httpRequest returns the http.Request of the bindable request, or of the first request it wraps.
+func httpRequest(r BindableRequest) (HttpBindableRequest, bool) {
+	hr, ok := findRequest[HTTPRequester](r)
+	if !ok || hr.HTTPRequest() == nil {
+		return HttpBindableRequest{}, false
+	}
+	return HttpBindableRequest{hr.HTTPRequest()}, true
+}
+
 func (r HttpBindableRequest) GetBody() io.Reader {
 	return r.Body
 }
@@ -43,6 +66,32 @@ func (r HttpBindableRequest) GetHeaders() url.Values {
 	return r.headersToValues(r.Header)
 }
 
+// GetMetadata returns the metadata of the request URL, filling the host and scheme
+// that are not set on server requests.
+func (r HttpBindableRequest) GetMetadata() url.Values {
+	u := *r.URL
+	if u.Host == "" {
+		u.Host = r.Host
+	}
+	if u.Scheme == "" {
+		u.Scheme = "http"
+		if r.TLS != nil {
+			u.Scheme = "https"
+		}
+	}
+	values := URLMetadata(&u)
+	values.Set(MetadataMethod, r.Method)
+	return values
+}
+
+func (r HttpBindableRequest) GetProto() string {
+	return r.Proto
+}
+
+func (r HttpBindableRequest) GetRemoteAddr() string {
+	return r.RemoteAddr
+}
+
 func (r HttpBindableRequest) GetContentLength() int64 {
 	return r.ContentLength
 }
@@ -58,8 +107,20 @@ func (r HttpBindableRequest) GetForm() (url.Values, error) {
 	return r.Form, nil
 }
 
+// GetPostForm returns the urlencoded form values of the body, without the URL query values.
+func (r HttpBindableRequest) GetPostForm() (url.Values, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	return r.PostForm, nil
+}
+
+// GetMultipartForm parses the multipart body, keeping up to maxBodySize bytes of its files in memory.
 func (r HttpBindableRequest) GetMultipartForm(maxBodySize int64) (*multipart.Form, error) {
-	return r.MultipartForm, r.ParseMultipartForm(maxBodySize)
+	if err := r.ParseMultipartForm(maxBodySize); err != nil {
+		return nil, err
+	}
+	return r.MultipartForm, nil
 }
 
 func NewHttpBindableRequest(r *http.Request) HttpBindableRequest {
@@ -68,29 +129,71 @@ func NewHttpBindableRequest(r *http.Request) HttpBindableRequest {
 
 // BindHttp binds an http.Request to a struct or map.
 func BindHttp(r *http.Request, i interface{}) error {
-	return GetHttpBinder().Bind(r, i)
+	b, err := requestHttpBinder(r)
+	if err != nil {
+		return err
+	}
+	return b.Bind(r, i)
 }
 
 // BindHttpBody binds an http.Request body to a struct or map.
 func BindHttpBody(r *http.Request, i interface{}) error {
-	return GetHttpBinder().BindBody(r, i)
+	b, err := requestHttpBinder(r)
+	if err != nil {
+		return err
+	}
+	return b.BindBody(r, i)
 }
 
 func BindHttpPathParms(r *http.Request, i interface{}) error {
-	return GetHttpBinder().BindPathParams(r, i)
+	b, err := requestHttpBinder(r)
+	if err != nil {
+		return err
+	}
+	return b.BindPathParams(r, i)
 }
 
 func BindHttpQueryParams(r *http.Request, i interface{}) error {
-	return GetHttpBinder().BindQueryParams(r, i)
+	b, err := requestHttpBinder(r)
+	if err != nil {
+		return err
+	}
+	return b.BindQueryParams(r, i)
+}
+
+func BindHttpRequestMetadata(r *http.Request, i interface{}) error {
+	b, err := requestHttpBinder(r)
+	if err != nil {
+		return err
+	}
+	return b.BindRequestMetadata(r, i)
 }
 
 func BindHttpHeaders(r *http.Request, i interface{}) error {
-	return GetHttpBinder().BindHeaders(r, i)
+	b, err := requestHttpBinder(r)
+	if err != nil {
+		return err
+	}
+	return b.BindHeaders(r, i)
+}
+
+// BindHttpCandidates binds an http.Request into the first matching candidate, see BindCandidates.
+func BindHttpCandidates(r *http.Request, versionHeader string, candidates ...Candidate) (int, interface{}, error) {
+	return BindCandidates(NewHttpBindableRequest(r), versionHeader, candidates...)
 }
 
 func GetHttpBinder() *HttpBinder {
+	defaultBinderMu.RLock()
+	hb := DefaultHttpBinder
+	defaultBinderMu.RUnlock()
+	if hb != nil {
+		return hb
+	}
+	b := GetBinder()
+	defaultBinderMu.Lock()
+	defer defaultBinderMu.Unlock()
 	if DefaultHttpBinder == nil {
-		DefaultHttpBinder = NewHttpBinder()
+		DefaultHttpBinder = &HttpBinder{Binder: b}
 	}
 	return DefaultHttpBinder
 }
@@ -119,6 +222,70 @@ func (b *HttpBinder) BindQueryParams(r *http.Request, i interface{}) error {
 	return b.Binder.BindQueryParams(NewHttpBindableRequest(r), i)
 }
 
+// BindRequestMetadata binds the request metadata when the binder is a *DefaultBinder.
+func (b *HttpBinder) BindRequestMetadata(r *http.Request, i interface{}) error {
+	if db, ok := b.Binder.(*DefaultBinder); ok {
+		return db.BindRequestMetadata(NewHttpBindableRequest(r), i)
+	}
+	return nil
+}
+
+// BindCSRF binds the CSRF token when the binder is a *DefaultBinder.
+func (b *HttpBinder) BindCSRF(r *http.Request, i interface{}) error {
+	if db, ok := b.Binder.(*DefaultBinder); ok {
+		return db.BindCSRF(NewHttpBindableRequest(r), i)
+	}
+	return nil
+}
+
+// BindAuth binds the Authorization credentials when the binder is a *DefaultBinder.
+func (b *HttpBinder) BindAuth(r *http.Request, i interface{}) error {
+	if db, ok := b.Binder.(*DefaultBinder); ok {
+		return db.BindAuth(NewHttpBindableRequest(r), i)
+	}
+	return nil
+}
+
+// BindClaims binds the verified claims when the binder is a *DefaultBinder.
+func (b *HttpBinder) BindClaims(r *http.Request, i interface{}) error {
+	if db, ok := b.Binder.(*DefaultBinder); ok {
+		return db.BindClaims(NewHttpBindableRequest(r), i)
+	}
+	return nil
+}
+
+// BindContextValues binds the request context values when the binder is a *DefaultBinder.
+func (b *HttpBinder) BindContextValues(r *http.Request, i interface{}) error {
+	if db, ok := b.Binder.(*DefaultBinder); ok {
+		return db.BindContextValues(NewHttpBindableRequest(r), i)
+	}
+	return nil
+}
+
+// BindSession binds the session values when the binder is a *DefaultBinder.
+func (b *HttpBinder) BindSession(r *http.Request, i interface{}) error {
+	if db, ok := b.Binder.(*DefaultBinder); ok {
+		return db.BindSession(NewHttpBindableRequest(r), i)
+	}
+	return nil
+}
+
+// BindEnv binds the environment fallback values when the binder is a *DefaultBinder.
+func (b *HttpBinder) BindEnv(r *http.Request, i interface{}) error {
+	if db, ok := b.Binder.(*DefaultBinder); ok {
+		return db.BindEnv(NewHttpBindableRequest(r), i)
+	}
+	return nil
+}
+
+// EffectiveMethod returns the request method after the method override when the binder is a *DefaultBinder.
+func (b *HttpBinder) EffectiveMethod(r *http.Request) string {
+	if db, ok := b.Binder.(*DefaultBinder); ok {
+		return db.EffectiveMethod(NewHttpBindableRequest(r))
+	}
+	return r.Method
+}
+
 func (b *HttpBinder) BindHeaders(r *http.Request, i interface{}) error {
 	return b.Binder.BindHeaders(NewHttpBindableRequest(r), i)
 }
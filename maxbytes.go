@@ -0,0 +1,134 @@
+package binder
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxBytes returns the max size of the string values of the field, selected with TagOption(field, "maxbytes"),
+// i.e. `maxbytes:"256"`, or 0 when it has none or the field does not hold strings.
+func maxBytes(field reflect.StructField) (int, error) {
+	if !strings.Contains(string(field.Tag), "maxbytes") || !holdsStrings(field.Type) {
+		return 0, nil
+	}
+	option := TagOption(field, "maxbytes")
+	if option == "" {
+		return 0, nil
+	}
+	max, err := strconv.Atoi(option)
+	if err != nil || max <= 0 {
+		return 0, fmt.Errorf("invalid maxbytes %q of field %s", option, field.Name)
+	}
+	return max, nil
+}
+
+// holdsStrings reports whether the type is a string kind, or a pointer or slice of them.
+func holdsStrings(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+	}
+	return typ.Kind() == reflect.String
+}
+
+// truncateString truncates the value to max bytes without splitting a character.
+func truncateString(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	for max > 0 && !utf8.RuneStart(value[max]) {
+		max--
+	}
+	return value[:max]
+}
+
+// limitValues applies the `maxbytes` of the field to its input values, returning the values to bind: the same
+// values when none exceeds it, a truncated copy under StringSizeTruncate, or a *FieldSizeError.
+func (b *DefaultBinder) limitValues(field reflect.StructField, source string, name string, values []string) ([]string, error) {
+	max, err := maxBytes(field)
+	if err != nil || max == 0 {
+		return values, err
+	}
+	var limited []string
+	for i, value := range values {
+		if len(value) <= max {
+			continue
+		}
+		if b.StringSizePolicy != StringSizeTruncate {
+			return nil, &FieldSizeError{Source: source, Field: name, Max: max}
+		}
+		if limited == nil {
+			limited = append([]string(nil), values...)
+		}
+		limited[i] = truncateString(value, max)
+	}
+	if limited == nil {
+		return values, nil
+	}
+	b.warn(fmt.Sprintf("%s field %s truncated to the maximum of %d bytes", source, name, max))
+	return limited, nil
+}
+
+// limitData applies the `maxbytes` of the field to the values of the data bound to its elements.
+func (b *DefaultBinder) limitData(field reflect.StructField, source string, name string, data map[string][]string) error {
+	for key, values := range data {
+		limited, err := b.limitValues(field, source, name, values)
+		if err != nil {
+			return err
+		}
+		data[key] = limited
+	}
+	return nil
+}
+
+// limitDecodedStrings applies the `maxbytes` of the fields of a decoded body to their values, failing on
+// invalid `maxbytes` like the other sources do.
+func (b *DefaultBinder) limitDecodedStrings(i interface{}, source string) error {
+	val := reflect.ValueOf(i)
+	match := func(field reflect.StructField) bool {
+		max, err := maxBytes(field)
+		return max > 0 || err != nil
+	}
+	if val.Kind() != reflect.Ptr || val.IsNil() || !hasMatchingFields(val.Type(), match, map[reflect.Type]bool{}) {
+		return nil
+	}
+	var err error
+	walkFields(val.Elem(), "", match, func(_ string, field reflect.Value, typeField reflect.StructField) {
+		if err != nil {
+			return
+		}
+		var max int
+		if max, err = maxBytes(typeField); err == nil {
+			err = b.limitString(field, max, source, bodyFieldName(source, typeField))
+		}
+	})
+	return err
+}
+
+// limitString applies the max size to a string value, or to the strings of a pointer or slice.
+func (b *DefaultBinder) limitString(value reflect.Value, max int, source string, name string) error {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if !value.IsNil() {
+			return b.limitString(value.Elem(), max, source, name)
+		}
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			if err := b.limitString(value.Index(i), max, source, name); err != nil {
+				return err
+			}
+		}
+	case reflect.String:
+		if value.Len() <= max {
+			return nil
+		}
+		if b.StringSizePolicy != StringSizeTruncate {
+			return &FieldSizeError{Source: source, Field: name, Max: max}
+		}
+		value.SetString(truncateString(value.String(), max))
+		b.warn(fmt.Sprintf("%s field %s truncated to the maximum of %d bytes", source, name, max))
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package binder
+
+import (
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+)
+
+// Request metadata keys bound with the request tag, i.e. `request:"host"`.
+const (
+	MetadataMethod      = "method"       // request method, overridden by the MethodOverrideField when enabled
+	MetadataHost        = "host"         // host with port as sent by the client, i.e. `example.com:8080`
+	MetadataHostname    = "hostname"     // host without port
+	MetadataPort        = "port"         // port, empty when not explicit
+	MetadataScheme      = "scheme"       // `http` or `https`
+	MetadataPath        = "path"         // unescaped path
+	MetadataEscapedPath = "escaped_path" // escaped path
+	MetadataRawQuery    = "raw_query"    // encoded query without `?`
+	MetadataURL         = "url"          // full URL
+	MetadataProto       = "proto"        // protocol of the request, i.e. `HTTP/1.1`
+	MetadataRemoteAddr  = "remote_addr"  // network address of the peer, usually `ip:port`
+	MetadataRemoteIP    = "remote_ip"    // IP of the peer, ignoring the forwarding headers
+	MetadataClientIP    = "client_ip"    // client IP, resolved with the binder ClientIPResolver
+)
+
+// MetadataRequest is implemented by bindable requests exposing metadata (host, scheme, path...)
+// bound into fields with the request tag.
+type MetadataRequest interface {
+	GetMetadata() url.Values
+}
+
+// URLMetadata returns the metadata values of an URL.
+func URLMetadata(u *url.URL) url.Values {
+	host, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		host, port = u.Host, ""
+	}
+	return url.Values{
+		MetadataHost:        {u.Host},
+		MetadataHostname:    {host},
+		MetadataPort:        {port},
+		MetadataScheme:      {u.Scheme},
+		MetadataPath:        {u.Path},
+		MetadataEscapedPath: {u.EscapedPath()},
+		MetadataRawQuery:    {u.RawQuery},
+		MetadataURL:         {u.String()},
+	}
+}
+
+// GetMetadata returns the request metadata, or nil when the request does not implement MetadataRequest.
+func (b *DefaultBinder) GetMetadata(r BindableRequest) map[string][]string {
+	if mr, ok := findRequest[MetadataRequest](r); ok {
+		return mr.GetMetadata()
+	}
+	return nil
+}
+
+// BindRequestMetadata binds request metadata (host, scheme, path...) to fields with the request tag.
+// Only struct destinations are bound, so maps bound with Bind do not receive metadata.
+func (b *DefaultBinder) BindRequestMetadata(r BindableRequest, i interface{}) error {
+	if typ := reflect.TypeOf(i); typ == nil || typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	values := map[string][]string{}
+	for k, v := range b.GetMetadata(r) {
+		values[k] = v
+	}
+	if proto := r.GetProto(); proto != "" {
+		values[MetadataProto] = []string{proto}
+	}
+	if remoteAddr := r.GetRemoteAddr(); remoteAddr != "" {
+		values[MetadataRemoteAddr] = []string{remoteAddr}
+		if peer, ok := parseIP(remoteAddr); ok {
+			values[MetadataRemoteIP] = []string{peer.String()}
+		}
+		var client netip.Addr
+		if b.ClientIPResolver != nil {
+			client = b.ClientIPResolver.Resolve(remoteAddr, r.GetHeaders())
+		} else {
+			// without trusted proxies forwarding headers are ignored
+			client, _ = parseIP(remoteAddr)
+		}
+		if client.IsValid() {
+			values[MetadataClientIP] = []string{client.String()}
+		}
+	}
+	if _, ok := values[MetadataMethod]; ok && b.MethodOverrideField != "" {
+		values[MetadataMethod] = []string{b.EffectiveMethod(r)}
+	}
+	ctx, release := b.bindContext(r)
+	defer release()
+	if err := b.bindData(ctx, i, values, b.RequestTagName, nil); err != nil {
+		return err
+	}
+	return nil
+}
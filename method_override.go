@@ -0,0 +1,91 @@
+package binder
+
+import (
+	"net/http"
+	"strings"
+)
+
+// methodOverrides are the methods a POST form can be overridden to, see MethodOverrideField.
+var methodOverrides = map[string]bool{
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
+// EffectiveMethod returns the request method, overridden by the MethodOverrideField of a POST form
+// when enabled, i.e. `_method=PUT`. Only PUT, PATCH and DELETE overrides are accepted, and only from
+// the body: like Rack, a `?_method=DELETE` query param does not override the method.
+func (b *DefaultBinder) EffectiveMethod(r BindableRequest) string {
+	var method string
+	if methods := b.GetMetadata(r)[MetadataMethod]; len(methods) > 0 {
+		method = methods[0]
+	}
+	if b.MethodOverrideField == "" || method != http.MethodPost {
+		return method
+	}
+
+	if override := b.postFormValues(r)[b.MethodOverrideField]; len(override) > 0 {
+		if override := strings.ToUpper(strings.TrimSpace(override[0])); methodOverrides[override] {
+			return override
+		}
+	}
+	return method
+}
+
+// postFormValues returns the values of an urlencoded or multipart form body, without the URL query
+// values, or nil for other bodies and bodies exceeding MaxBodySize. Urlencoded bodies require a PostFormRequest.
+func (b *DefaultBinder) postFormValues(r BindableRequest) map[string][]string {
+	mediatype, _ := ParseMediaType(r.GetContentType())
+	if !isFormMediaType(mediatype) || b.limitBody(r) != nil {
+		return nil
+	}
+	switch mediatype {
+	case MIMEApplicationForm:
+		pr, ok := findRequest[PostFormRequest](r)
+		if !ok {
+			return nil
+		}
+		form, err := pr.GetPostForm()
+		if err != nil {
+			return nil
+		}
+		return form
+	case MIMEMultipartForm:
+		form, err := r.GetMultipartForm(b.MaxBodySize)
+		if err != nil || form == nil {
+			return nil
+		}
+		return form.Value
+	}
+	return nil
+}
+
+// withoutReservedFields returns the form values without the MethodOverrideField and the consumed
+// CSRF form field, so they are not bound into destination maps nor reported as unknown keys.
+func (b *DefaultBinder) withoutReservedFields(values map[string][]string) map[string][]string {
+	reserved := []string{}
+	if b.MethodOverrideField != "" {
+		reserved = append(reserved, b.MethodOverrideField)
+	}
+	if b.CSRF != nil && b.CSRF.Consume && b.CSRF.FormField != "" {
+		reserved = append(reserved, b.CSRF.FormField)
+	}
+
+	var result map[string][]string
+	for _, field := range reserved {
+		if _, ok := values[field]; !ok {
+			continue
+		}
+		if result == nil {
+			result = make(map[string][]string, len(values))
+			for k, v := range values {
+				result[k] = v
+			}
+		}
+		delete(result, field)
+	}
+	if result == nil {
+		return values
+	}
+	return result
+}
@@ -0,0 +1,112 @@
+package binder
+
+import (
+	"mime/multipart"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// fieldTagName returns the name of the source tag of the field, or its json tag name for the query
+// and form sources when UseJSONTagFallback is enabled and the field has no source tag.
+func (b *DefaultBinder) fieldTagName(field reflect.StructField, tag string) string {
+	if _, ok := field.Tag.Lookup(tag); ok || !b.UseJSONTagFallback || (tag != b.QueryTagName && tag != b.FormTagName) {
+		return tagName(field, tag)
+	}
+	if name := tagName(field, "json"); name != "-" {
+		return name
+	}
+	return ""
+}
+
+// usesFieldNameFallback reports whether the field without any tag is bound by its name from the source,
+// see UseFieldNameFallback and NameMapper.
+func (b *DefaultBinder) usesFieldNameFallback(field reflect.StructField, tag string) bool {
+	if (!b.UseFieldNameFallback && b.NameMapper == nil) || field.Tag != "" || field.Anonymous || !field.IsExported() {
+		return false
+	}
+	return tag == b.ParamTagName || tag == b.QueryTagName || tag == b.FormTagName || tag == b.HeaderTagName
+}
+
+// fallbackFieldName returns the name of the input matching the field name, ignoring case, `_` and `-`,
+// with the dot or bracket path of the key removed, or "" when there is none.
+// With a NameMapper, the mapped field name is returned instead.
+func (b *DefaultBinder) fallbackFieldName(fieldName string, data map[string][]string, dataFiles map[string][]*multipart.FileHeader) string {
+	if b.NameMapper != nil {
+		return b.NameMapper(fieldName)
+	}
+	normalized := normalizeFieldName(fieldName)
+	match := func(key string) string {
+		name := key
+		if i := strings.Index(name, "["); i >= 0 {
+			name = name[:i]
+		}
+		if b.DeepObjectSeparator != "" {
+			name, _, _ = strings.Cut(name, b.DeepObjectSeparator)
+		}
+		if normalizeFieldName(name) == normalized {
+			return name
+		}
+		return ""
+	}
+	// the smallest matching name is returned when several spellings are sent, to be deterministic
+	result := ""
+	for key := range data {
+		if name := match(key); name != "" && (result == "" || name < result) {
+			result = name
+		}
+	}
+	for key := range dataFiles {
+		if name := match(key); name != "" && (result == "" || name < result) {
+			result = name
+		}
+	}
+	return result
+}
+
+// normalizeFieldName lowers the name and removes `_` and `-`, so `user_id`, `userId` and `UserID` match.
+func normalizeFieldName(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+}
+
+// SnakeCaseName maps a Go field name to snake_case, i.e. `UserID` to `user_id`. Use it as NameMapper.
+func SnakeCaseName(name string) string {
+	return strings.Join(splitFieldName(name), "_")
+}
+
+// KebabCaseName maps a Go field name to kebab-case, i.e. `UserID` to `user-id`. Use it as NameMapper.
+func KebabCaseName(name string) string {
+	return strings.Join(splitFieldName(name), "-")
+}
+
+// CamelCaseName maps a Go field name to camelCase, i.e. `UserID` to `userId`. Use it as NameMapper.
+func CamelCaseName(name string) string {
+	words := splitFieldName(name)
+	for i := 1; i < len(words); i++ {
+		words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+	}
+	return strings.Join(words, "")
+}
+
+// splitFieldName splits a Go field name into lower case words, keeping acronyms together,
+// i.e. `HTTPServerID` is `http`, `server` and `id`.
+func splitFieldName(name string) []string {
+	runes := []rune(name)
+	words := []string{}
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		lowerToUpper := unicode.IsLower(runes[i-1]) && unicode.IsUpper(runes[i])
+		acronymEnd := unicode.IsUpper(runes[i-1]) && unicode.IsUpper(runes[i]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		digitBoundary := unicode.IsDigit(runes[i-1]) != unicode.IsDigit(runes[i]) && unicode.IsUpper(runes[i])
+		if lowerToUpper || acronymEnd || digitBoundary || runes[i] == '_' {
+			if word := strings.Trim(string(runes[start:i]), "_"); word != "" {
+				words = append(words, strings.ToLower(word))
+			}
+			start = i
+		}
+	}
+	if word := strings.Trim(string(runes[start:]), "_"); word != "" {
+		words = append(words, strings.ToLower(word))
+	}
+	return words
+}
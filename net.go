@@ -0,0 +1,60 @@
+package binder
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"reflect"
+)
+
+// ConvertAddr converts the value into a netip.Addr.
+func ConvertAddr(values []string, dst reflect.Value, _ reflect.StructField) error {
+	if len(values) == 0 || values[0] == "" {
+		return nil
+	}
+	addr, err := netip.ParseAddr(values[0])
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidIP, values[0])
+	}
+	dst.Set(reflect.ValueOf(addr))
+	return nil
+}
+
+// ConvertPrefix converts the value into a netip.Prefix.
+func ConvertPrefix(values []string, dst reflect.Value, _ reflect.StructField) error {
+	if len(values) == 0 || values[0] == "" {
+		return nil
+	}
+	prefix, err := netip.ParsePrefix(values[0])
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidCIDR, values[0])
+	}
+	dst.Set(reflect.ValueOf(prefix))
+	return nil
+}
+
+// ConvertIP converts the value into a net.IP.
+func ConvertIP(values []string, dst reflect.Value, _ reflect.StructField) error {
+	if len(values) == 0 || values[0] == "" {
+		return nil
+	}
+	ip := net.ParseIP(values[0])
+	if ip == nil {
+		return fmt.Errorf("%w: %q", ErrInvalidIP, values[0])
+	}
+	dst.Set(reflect.ValueOf(ip))
+	return nil
+}
+
+// ConvertIPNet converts the value into a net.IPNet holding the network of the CIDR, i.e. `10.0.0.0/8`.
+func ConvertIPNet(values []string, dst reflect.Value, _ reflect.StructField) error {
+	if len(values) == 0 || values[0] == "" {
+		return nil
+	}
+	_, ipNet, err := net.ParseCIDR(values[0])
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidCIDR, values[0])
+	}
+	dst.Set(reflect.ValueOf(*ipNet))
+	return nil
+}
@@ -0,0 +1,43 @@
+package binder
+
+import (
+	"net/url"
+	"strings"
+)
+
+// QueryNormalizer transforms the raw query params before binding, i.e. to strip tracking params.
+type QueryNormalizer func(values url.Values) url.Values
+
+// StripQueryPrefixes returns a normalizer removing the params starting with any of the prefixes, i.e. `utm_`.
+func StripQueryPrefixes(prefixes ...string) QueryNormalizer {
+	return func(values url.Values) url.Values {
+		result := url.Values{}
+		for key, v := range values {
+			strip := false
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(key, prefix) {
+					strip = true
+					break
+				}
+			}
+			if !strip {
+				result[key] = v
+			}
+		}
+		return result
+	}
+}
+
+// RenameQueryKeys returns a normalizer renaming legacy keys, appending their values to the new key.
+func RenameQueryKeys(names map[string]string) QueryNormalizer {
+	return func(values url.Values) url.Values {
+		result := url.Values{}
+		for key, v := range values {
+			if name, ok := names[key]; ok {
+				key = name
+			}
+			result[key] = append(result[key], v...)
+		}
+		return result
+	}
+}
@@ -0,0 +1,70 @@
+package binder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+)
+
+// Optional is a field that tells an input left out of the request from an input set to the zero value,
+// without a pointer, i.e. an `Age binder.Optional[int]` field. Present is set when the key is supplied
+// by a JSON body or the path, query, form and header sources, even with an empty or null value.
+type Optional[T any] struct {
+	Value   T
+	Present bool
+}
+
+// Get returns the value and whether it was supplied.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Present
+}
+
+// UnmarshalJSON decodes the value, a JSON null resetting it to the zero value.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Present = true
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		var zero T
+		o.Value = zero
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
+}
+
+// MarshalJSON encodes the value, or null when it was not supplied.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.Present {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.Value)
+}
+
+// optionalField returns the value to convert the input into and the presence flag to set.
+func (o *Optional[T]) optionalField() (reflect.Value, *bool) {
+	return reflect.ValueOf(&o.Value).Elem(), &o.Present
+}
+
+// optionalValue is the interface of the Optional fields, bound by converting the input into their value.
+type optionalValue interface {
+	optionalField() (reflect.Value, *bool)
+}
+
+// setOptionalValues converts the input values into the value of an Optional field, marking it present.
+// It reports false when the field is not an Optional.
+func (b *DefaultBinder) setOptionalValues(ctx context.Context, structField reflect.Value, typeField reflect.StructField, tag string, inputFieldName string, inputKey string, inputValue []string) (bool, error) {
+	if structField.Kind() != reflect.Struct || !structField.CanAddr() {
+		return false, nil
+	}
+	optional, ok := structField.Addr().Interface().(optionalValue)
+	if !ok {
+		return false, nil
+	}
+	value, present := optional.optionalField()
+	field := typeField
+	field.Type = value.Type()
+	if err := b.setFieldValues(ctx, value, field, tag, inputFieldName, inputKey, inputValue); err != nil {
+		return true, err
+	}
+	*present = true
+	return true, nil
+}
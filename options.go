@@ -0,0 +1,134 @@
+package binder
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+// BindOption changes the settings of a single bind, see BindWith.
+type BindOption func(b *DefaultBinder)
+
+// WithMaxBodySize sets the max body size of the bind, i.e. for a multipart upload.
+func WithMaxBodySize(size int64) BindOption {
+	return func(b *DefaultBinder) { b.MaxBodySize = size }
+}
+
+// WithMaxArraySize sets the max size of the arrays bound by the bind.
+func WithMaxArraySize(size int) BindOption {
+	return func(b *DefaultBinder) { b.MaxArraySize = size }
+}
+
+// WithStrict enables, or disables, the strict mode of the bind: unknown query, form and JSON keys,
+// and tagged fields that cannot be set, are reported as errors.
+func WithStrict(strict bool) BindOption {
+	return func(b *DefaultBinder) {
+		b.StrictKeys = strict
+		b.StrictFields = strict
+		if s, ok := b.JSONSerializer.(DefaultJSONSerializer); ok {
+			s.StrictJSON = strict
+			b.JSONSerializer = s
+		}
+	}
+}
+
+// Only restricts the bind to the named sources of BindOrder, i.e. `Only(SourceQuery, SourceBody)`,
+// keeping their order. Unknown names fail the bind with ErrUnknownSource.
+func Only(sources ...string) BindOption {
+	return func(b *DefaultBinder) {
+		allowed := map[string]bool{}
+		for _, name := range sources {
+			if _, ok := b.bindStep(name); !ok {
+				b.optionErr = fmt.Errorf("%w: %q", ErrUnknownSource, name)
+				return
+			}
+			allowed[name] = true
+		}
+		steps := make([]string, 0, len(b.BindOrder))
+		for _, name := range b.BindOrder {
+			if allowed[name] {
+				steps = append(steps, name)
+			}
+		}
+		b.BindOrder = steps
+	}
+}
+
+// WithBindRequestMetadata adds BindRequestMetadata to the BindOrder, after the env fallbacks and before the
+// request sources, or removes it. The request metadata is not bound by default, see DefaultBindRequestMetadata.
+func WithBindRequestMetadata(enabled bool) BindOption {
+	return func(b *DefaultBinder) { b.setBindStep(SourceMetadata, SourceEnv, enabled) }
+}
+
+// WithBindHeaders adds BindHeaders to the BindOrder, right after the query params, or removes it.
+// The headers are not bound by default, see DefaultBindHeaders.
+func WithBindHeaders(enabled bool) BindOption {
+	return func(b *DefaultBinder) { b.setBindStep(SourceHeader, SourceQuery, enabled) }
+}
+
+// setBindStep adds the named source to the BindOrder right after the source after, or first when the BindOrder
+// does not hold it, or removes the source when disabled.
+func (b *DefaultBinder) setBindStep(source string, after string, enabled bool) {
+	steps := make([]string, 0, len(b.BindOrder)+1)
+	index := 0
+	for _, name := range b.BindOrder {
+		if name == source {
+			continue
+		}
+		steps = append(steps, name)
+		if name == after {
+			index = len(steps)
+		}
+	}
+	if enabled {
+		steps = slices.Insert(steps, index, source)
+	}
+	b.BindOrder = steps
+}
+
+// withOptions returns the binder of a bind with the options, a copy of the binder when there are any.
+func (b *DefaultBinder) withOptions(opts []BindOption) (*DefaultBinder, error) {
+	if len(opts) == 0 {
+		return b, nil
+	}
+	c := b.Clone()
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, c.optionErr
+}
+
+// BindWith binds like Bind with per-call options, i.e. `b.BindWith(r, i, binder.WithMaxBodySize(1<<30))`,
+// without changing the binder.
+func (b *DefaultBinder) BindWith(r BindableRequest, i interface{}, opts ...BindOption) error {
+	c, err := b.withOptions(opts)
+	if err != nil {
+		return err
+	}
+	return c.Bind(r, i)
+}
+
+// BindBodyWith binds like BindBody with per-call options, without changing the binder.
+func (b *DefaultBinder) BindBodyWith(r BindableRequest, i interface{}, opts ...BindOption) error {
+	c, err := b.withOptions(opts)
+	if err != nil {
+		return err
+	}
+	return c.runStep(r, i, namedStep{name: SourceBody, fn: c.BindBody}, map[string]string{})
+}
+
+// BindWith binds like Bind with per-call options when the binder is a *DefaultBinder; other binders ignore them.
+func (b *HttpBinder) BindWith(r *http.Request, i interface{}, opts ...BindOption) error {
+	if db, ok := b.Binder.(*DefaultBinder); ok {
+		return db.BindWith(NewHttpBindableRequest(r), i, opts...)
+	}
+	return b.Bind(r, i)
+}
+
+// BindBodyWith binds like BindBody with per-call options when the binder is a *DefaultBinder; other binders ignore them.
+func (b *HttpBinder) BindBodyWith(r *http.Request, i interface{}, opts ...BindOption) error {
+	if db, ok := b.Binder.(*DefaultBinder); ok {
+		return db.BindBodyWith(NewHttpBindableRequest(r), i, opts...)
+	}
+	return b.BindBody(r, i)
+}
@@ -0,0 +1,55 @@
+package binder
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ParamSpec describes a wildcard of a path pattern, i.e. `{id}` or `{path...}`.
+type ParamSpec struct {
+	Name     string // name of the wildcard, without the dots nor the constraint of `{id:[0-9]+}` routers
+	Wildcard bool   // the wildcard matches the remaining segments, i.e. `{path...}`
+	Position int    // index of the path segment holding the wildcard, i.e. 1 for `/users/{id}`
+}
+
+// ParsePathPattern returns the wildcards of a path pattern, as matched by PathMatcherRegexp, in order.
+// Method and host prefixes (`GET example.com/users/{id}`) and the `{$}` end anchor of net/http patterns
+// are ignored, so routing layers and documentation generators share the interpretation of the binder.
+func ParsePathPattern(pattern string) []ParamSpec {
+	return parsePathPattern(PathMatcherRegexp, pattern)
+}
+
+// ParsePathPattern returns the wildcards of a path pattern as matched by the PathMatcher of the binder.
+func (b *DefaultBinder) ParsePathPattern(pattern string) []ParamSpec {
+	return parsePathPattern(b.PathMatcher, pattern)
+}
+
+func parsePathPattern(matcher *regexp.Regexp, pattern string) []ParamSpec {
+	// net/http patterns are `[METHOD ][HOST]/[PATH]`
+	if _, rest, ok := strings.Cut(pattern, " "); ok {
+		pattern = strings.TrimLeft(rest, " ")
+	}
+	if i := strings.Index(pattern, "/"); i > 0 && strings.ContainsAny(pattern[:i], ".:") && !strings.Contains(pattern[:i], "{") {
+		// a host, i.e. `example.com` or `localhost:8080`
+		pattern = pattern[i:]
+	}
+
+	var specs []ParamSpec
+	for _, match := range matcher.FindAllStringSubmatchIndex(pattern, -1) {
+		if len(match) < 4 || match[2] < 0 {
+			continue
+		}
+		name := pattern[match[2]:match[3]]
+		if name == "$" {
+			continue
+		}
+		name, _, _ = strings.Cut(name, ":")
+		name, wildcard := strings.CutSuffix(name, "...")
+		position := strings.Count(pattern[:match[0]], "/")
+		if strings.HasPrefix(pattern, "/") {
+			position--
+		}
+		specs = append(specs, ParamSpec{Name: name, Wildcard: wildcard, Position: position})
+	}
+	return specs
+}
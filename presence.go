@@ -0,0 +1,138 @@
+package binder
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// presenceKey identifies a field by address and type, as the first field of a struct shares its address.
+type presenceKey struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+// markPresent records that the request supplied the key of the field, when a report is requested.
+func (b *DefaultBinder) markPresent(field reflect.Value) {
+	if b.present != nil {
+		b.present[presenceKey{addr: field.UnsafeAddr(), typ: field.Type()}] = true
+	}
+}
+
+// recordPresent adds the paths of the fields marked present during a step to the report.
+func (b *DefaultBinder) recordPresent(i interface{}) {
+	if len(b.present) == 0 {
+		return
+	}
+	if b.report.Present == nil {
+		b.report.Present = map[string]bool{}
+	}
+	walkFieldPaths(reflect.ValueOf(i), "", func(path string, field reflect.Value) {
+		if b.present[presenceKey{addr: field.UnsafeAddr(), typ: field.Type()}] {
+			b.report.Present[path] = true
+		}
+	})
+}
+
+// walkFieldPaths calls visit with the exported fields reachable from the value and their path,
+// walking into structs and non-nil pointers. Embedded structs are not part of the path.
+func walkFieldPaths(val reflect.Value, path string, visit func(path string, field reflect.Value)) {
+	switch val.Kind() {
+	case reflect.Ptr:
+		if !val.IsNil() {
+			walkFieldPaths(val.Elem(), path, visit)
+		}
+	case reflect.Struct:
+		typ := val.Type()
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldPath := path
+			if !field.Anonymous {
+				fieldPath = joinPath(path, field.Name)
+				visit(fieldPath, val.Field(i))
+			}
+			walkFieldPaths(val.Field(i), fieldPath, visit)
+		}
+	}
+}
+
+// capturePresentJSON records the JSON body read by the deserializer when a report is requested, returning
+// a function marking the fields whose keys are present in the body, even with a zero value, after decoding.
+func (b *DefaultBinder) capturePresentJSON(r BindableRequest, mediaType string, i interface{}) func() {
+	if b.present == nil {
+		return func() {}
+	}
+	body, ok := teeJSONBody(r, mediaType)
+	if !ok {
+		return func() {}
+	}
+	return func() {
+		b.markJSONPresence(body.Bytes(), reflect.ValueOf(i))
+	}
+}
+
+// markJSONPresence marks the fields of the struct value whose keys are present in the JSON object,
+// matching the keys like encoding/json does, and the fields of the nested objects.
+func (b *DefaultBinder) markJSONPresence(raw []byte, val reflect.Value) {
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return
+	}
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return
+	}
+	fields := map[string]reflect.Value{}
+	b.jsonFields(val, fields)
+	for key, value := range values {
+		field, ok := fields[key]
+		if !ok {
+			for name, f := range fields {
+				if strings.EqualFold(name, key) {
+					field, ok = f, true
+					break
+				}
+			}
+		}
+		if !ok {
+			continue
+		}
+		b.markPresent(field)
+		b.markJSONPresence(value, field)
+	}
+}
+
+// jsonFields adds the settable fields of the struct value by JSON name, flattening the embedded structs
+// like encoding/json does. The fields the decoder may not set (`bind:"-"`, `,readonly`) are skipped.
+func (b *DefaultBinder) jsonFields(val reflect.Value, fields map[string]reflect.Value) {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		value := val.Field(i)
+		if b.isExcluded(field, "json") || isReadOnly(field) {
+			continue
+		}
+		if tagName(field, "json") == "" && field.Anonymous {
+			if value.Kind() == reflect.Ptr && !value.IsNil() {
+				value = value.Elem()
+			}
+			if value.Kind() == reflect.Struct {
+				b.jsonFields(value, fields)
+				continue
+			}
+		}
+		if field.IsExported() && value.CanSet() {
+			if _, ok := fields[jsonFieldName(field)]; !ok {
+				fields[jsonFieldName(field)] = value
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package binder
+
+import (
+	"io"
+	"slices"
+	"strings"
+)
+
+// BodyProcessor wraps the body of a request before it is decoded, i.e. to decrypt, decompress or de-armor it.
+// The request gives access to the headers the processor may need, like a key id.
+type BodyProcessor func(r BindableRequest, body io.Reader) (io.Reader, error)
+
+// UseBodyProcessor registers a body processor for a media type, i.e. `application/json`, or for every media type
+// when it is empty. The processors of every media type run first, then the ones of the media type of the body,
+// in their registration order. Register the processors before sharing the binder, or on a copy with With.
+func (b *DefaultBinder) UseBodyProcessor(mediaType string, fn BodyProcessor) {
+	if b.BodyProcessors == nil {
+		b.BodyProcessors = map[string][]BodyProcessor{}
+	}
+	mediaType = strings.ToLower(mediaType)
+	b.BodyProcessors[mediaType] = append(b.BodyProcessors[mediaType], fn)
+}
+
+// processBody replaces the body read by the decoders with the one returned by the processors, which read the
+// body through its Content-Length check. The processed body is capped at MaxBodySize like the others, i.e. for
+// gzip bombs. Form bodies are parsed by the request itself, so they are only processed for an HttpBindableRequest.
+func (b *DefaultBinder) processBody(r *mediaTypeRequest) error {
+	processors := slices.Concat(b.BodyProcessors[""], b.BodyProcessors[r.mediaType])
+	if len(processors) == 0 {
+		return nil
+	}
+	hr, isHttp := httpRequest(r)
+	if !isHttp && isFormMediaType(r.mediaType) {
+		return nil
+	}
+	body := r.GetBody()
+	for _, process := range processors {
+		var err error
+		if body, err = process(r, body); err != nil {
+			return err
+		}
+	}
+	if b.MaxBodySize > 0 {
+		body = newMaxBodyReader(io.NopCloser(body), b.MaxBodySize)
+	}
+	r.body = body
+	if isHttp && isFormMediaType(r.mediaType) {
+		hr.Body = io.NopCloser(body)
+	}
+	return nil
+}
+
+// isFormMediaType reports whether bodies of the media type are parsed by the request itself.
+func isFormMediaType(mediaType string) bool {
+	return mediaType == MIMEApplicationForm || mediaType == MIMEMultipartForm
+}
@@ -0,0 +1,76 @@
+package binder
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"reflect"
+)
+
+// RawBody receives the unparsed request body in BindBody, alongside the other fields bound from it,
+// i.e. to verify a signature of the payload. Tag the field with `json:"-"` so the decoder leaves it alone.
+type RawBody []byte
+
+var rawBodyType = reflect.TypeOf(RawBody(nil))
+
+// isRawBodyField reports whether the field receives the raw body: a RawBody, or a []byte tagged with `body:"raw"`.
+func isRawBodyField(field reflect.StructField) bool {
+	if field.Type == rawBodyType {
+		return true
+	}
+	return field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Uint8 && field.Tag.Get("body") == "raw"
+}
+
+// rawBodyField returns the raw body field of the struct destination.
+func rawBodyField(i interface{}) (reflect.Value, bool) {
+	val := reflect.ValueOf(i)
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	val = val.Elem()
+	typ := val.Type()
+	for index := 0; index < typ.NumField(); index++ {
+		if field := typ.Field(index); field.IsExported() && isRawBodyField(field) {
+			return val.Field(index), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// captureRawBody reads the body, up to MaxBodySize (no limit when 0 or less), when the destination has a raw
+// body field, and replays it to the decoders. It returns a function setting the field once the body is bound.
+// Form bodies are parsed by the request itself, so they are only captured from an HttpBindableRequest, whose
+// Body is replaced by the captured copy: the handler can read the body again, and the server still closes
+// the original one.
+func (b *DefaultBinder) captureRawBody(r *mediaTypeRequest, i interface{}) (func(), error) {
+	field, ok := rawBodyField(i)
+	if !ok {
+		return func() {}, nil
+	}
+	hr, isHttp := httpRequest(r)
+	if !isHttp && isFormMediaType(r.mediaType) {
+		return func() {}, nil
+	}
+	body := r.GetBody()
+	if body == nil {
+		return func() {}, nil
+	}
+	if b.MaxBodySize > 0 && b.MaxBodySize < math.MaxInt64 {
+		// one more byte tells a body of exactly MaxBodySize bytes from a larger one
+		body = io.LimitReader(body, b.MaxBodySize+1)
+	}
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, bodyLengthError(r, err)
+	}
+	if b.MaxBodySize > 0 && int64(len(raw)) > b.MaxBodySize {
+		return nil, &BodySizeError{MaxBodySize: b.MaxBodySize}
+	}
+	r.body = bytes.NewReader(raw)
+	if isHttp {
+		hr.Body = io.NopCloser(bytes.NewReader(raw))
+	}
+	return func() {
+		field.SetBytes(raw)
+	}, nil
+}
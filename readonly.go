@@ -0,0 +1,64 @@
+package binder
+
+import (
+	"mime/multipart"
+	"reflect"
+	"strings"
+)
+
+// isReadOnly reports whether a tag of the field has the `,readonly` option, i.e. `json:"id,readonly"`
+// or `bind:",readonly"`. Like OpenAPI readOnly properties, such fields are set by the server and
+// are never bound from any source.
+func isReadOnly(field reflect.StructField) bool {
+	if !strings.Contains(string(field.Tag), "readonly") {
+		return false
+	}
+	return eachTagValue(field.Tag, func(value string) bool {
+		return tagOptions(value, func(option string) bool {
+			return option == "readonly"
+		})
+	})
+}
+
+// suppliesInput reports whether the data or files have the named key or one of its nested keys,
+// i.e. `owner`, `owner[]`, `owner.id` or `owner[id]`, ignoring case if asked like the lookup of the values.
+func suppliesInput(data map[string][]string, files map[string][]*multipart.FileHeader, name string, separator string, caseInsensitive bool) bool {
+	matches := func(key string) bool {
+		rest, ok := cutKeyPrefix(key, name, caseInsensitive)
+		if !ok {
+			return false
+		}
+		return rest == "" || strings.HasPrefix(rest, "[") || (separator != "" && strings.HasPrefix(rest, separator))
+	}
+	for key := range data {
+		if matches(key) {
+			return true
+		}
+	}
+	for key := range files {
+		if matches(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// readOnlyError returns a *ReadOnlyFieldError when the policy is ReadOnlyError and the request
+// supplied a value for the field, nil otherwise.
+func (b *DefaultBinder) readOnlyError(source string, field string, supplied bool) error {
+	if !supplied || b.ReadOnlyPolicy != ReadOnlyError {
+		return nil
+	}
+	return &ReadOnlyFieldError{Source: source, Field: field}
+}
+
+// bodyFieldName returns the key of the field in a body of the source, as declared by its json or xml tag,
+// or the name of the field, like the decoders match it.
+func bodyFieldName(source string, field reflect.StructField) string {
+	if tag := bodySource(source); tag == "json" || tag == "xml" {
+		if name := tagName(field, tag); name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
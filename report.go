@@ -0,0 +1,226 @@
+package binder
+
+import (
+	"mime/multipart"
+	"reflect"
+)
+
+// BindReport records which fields a bind set and from which source, see WithReport.
+type BindReport struct {
+	// Fields maps the path of the fields set by the bind, i.e. `Address.City`, to the name of the source
+	// that set them last: `path`, `query`, `header`, `body` or the name of a registered source.
+	// Fields set to the value they already had are not reported.
+	Fields map[string]string
+	// Present holds the path of the fields whose key the request supplied, even with a zero value,
+	// from the JSON bodies and the path, query, header and form sources. See WasSet.
+	Present map[string]bool
+	// Warnings lists the input the bind dropped instead of failing, i.e. the elements exceeding
+	// MaxArraySize under ArraySizeTruncate.
+	Warnings []string
+	// Stats describe the input processed by the bind, i.e. for anomaly detection.
+	Stats BindStats
+}
+
+// BindStats are the statistics of the input processed by a bind, see BindReport.
+type BindStats struct {
+	BodyBytes      int64          // bytes of the body read by the bind
+	Keys           map[string]int // number of keys by source: path, query, header and body for form bodies
+	Files          int            // number of uploaded files
+	MaxValueLength int            // length of the largest value of the path, query, header and form sources
+}
+
+// Source returns the source that set the field during the bind, or "" when the field was not set.
+func (r *BindReport) Source(field string) string {
+	return r.Fields[field]
+}
+
+// WasSet reports whether the request supplied the key of the field, i.e. to tell a field omitted from
+// a PATCH body from a field explicitly set to its zero value.
+func (r *BindReport) WasSet(field string) bool {
+	return r.Present[field]
+}
+
+// WithReport fills the report with the fields set by the bind and their sources, i.e. to debug the
+// binding order. Fields of promoted embedded structs are reported by their promoted name.
+func WithReport(report *BindReport) BindOption {
+	return func(b *DefaultBinder) { b.report = report }
+}
+
+// runStep runs a step of the bind, applying the MergePolicy to the fields it changed and recording them
+// when a report is requested. setBy holds the source that set each field earlier in the bind.
+func (b *DefaultBinder) runStep(r BindableRequest, i interface{}, step namedStep, setBy map[string]string) error {
+	if b.report == nil && b.MergePolicy == MergeLastWins {
+		return step.fn(r, i)
+	}
+	before := map[string]reflect.Value{}
+	collectLeaves(reflect.ValueOf(i), "", before)
+	if b.report != nil {
+		b.present = map[presenceKey]bool{}
+		defer func() { b.present = nil }()
+	}
+	if err := step.fn(r, i); err != nil {
+		return err
+	}
+	name := step.name
+	var conflict error
+	walkLeaves(reflect.ValueOf(i), "", func(path string, field reflect.Value) {
+		prev, ok := before[path]
+		if !ok {
+			// fields of the structs allocated by the step
+			prev = reflect.Zero(field.Type())
+		}
+		if reflect.DeepEqual(prev.Interface(), field.Interface()) {
+			return
+		}
+		// the env values are fallbacks, overridden by any source whatever the policy, and the values of
+		// the server are not supplied by the client, so they override the request values whatever the policy
+		override := setBy[path] == SourceEnv || serverSource(name)
+		switch b.MergePolicy {
+		case MergeFirstWins, MergeSkipIfNonZero:
+			if !override && (setBy[path] != "" || (b.MergePolicy == MergeSkipIfNonZero && !prev.IsZero())) {
+				field.Set(prev)
+				return
+			}
+		case MergeErrorOnConflict:
+			if !override && setBy[path] != "" && conflict == nil {
+				conflict = &MergeConflictError{Field: path, Previous: setBy[path], Source: name}
+			}
+		}
+		setBy[path] = name
+		if b.report != nil {
+			if b.report.Fields == nil {
+				b.report.Fields = map[string]string{}
+			}
+			b.report.Fields[path] = name
+		}
+	})
+	if conflict != nil {
+		return conflict
+	}
+	if b.report != nil {
+		b.recordPresent(i)
+	}
+	return nil
+}
+
+// serverSource reports whether the named source binds values of the server instead of the request.
+func serverSource(name string) bool {
+	return name == SourceClaims || name == SourceSession || name == SourceContext
+}
+
+// warn records a warning in the report of the bind, if any.
+func (b *DefaultBinder) warn(warning string) {
+	if b.report != nil {
+		b.report.Warnings = append(b.report.Warnings, warning)
+	}
+}
+
+// recordInput adds the keys, files and values of the source to the stats of the report, if any.
+func (b *DefaultBinder) recordInput(source string, data map[string][]string, files map[string][]*multipart.FileHeader) {
+	if b.report == nil {
+		return
+	}
+	stats := &b.report.Stats
+	if stats.Keys == nil {
+		stats.Keys = map[string]int{}
+	}
+	stats.Keys[source] += len(data) + len(files)
+	for _, values := range data {
+		for _, value := range values {
+			stats.MaxValueLength = max(stats.MaxValueLength, len(value))
+		}
+	}
+	for _, fileHeaders := range files {
+		stats.Files += len(fileHeaders)
+	}
+}
+
+// recordBody adds the size of the body to the stats of the report, if any: the bytes read by the
+// deserializer, or the Content-Length of the bodies parsed by the request itself.
+func (b *DefaultBinder) recordBody(r BindableRequest) {
+	if b.report == nil {
+		return
+	}
+	if mr, ok := r.(*mediaTypeRequest); ok {
+		if cr, ok := mr.body.(*contentLengthReader); ok {
+			b.report.Stats.BodyBytes += cr.contentLength - cr.remaining
+			return
+		}
+	}
+	b.report.Stats.BodyBytes += r.GetContentLength()
+}
+
+// collectLeaves saves a copy of the values of the leaves of the value by path, see walkLeaves.
+func collectLeaves(val reflect.Value, path string, leaves map[string]reflect.Value) {
+	walkLeaves(val, path, func(path string, field reflect.Value) {
+		leaves[path] = copyValue(field)
+	})
+}
+
+// walkLeaves calls visit with the exported fields reachable from the value and their path, walking into
+// structs and non-nil struct pointers. Structs without exported fields (time.Time...) are leaves.
+func walkLeaves(val reflect.Value, path string, visit func(path string, field reflect.Value)) {
+	switch val.Kind() {
+	case reflect.Ptr:
+		if !val.IsNil() && val.Elem().Kind() == reflect.Struct {
+			walkLeaves(val.Elem(), path, visit)
+			return
+		}
+	case reflect.Struct:
+		typ := val.Type()
+		walked := false
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			walked = true
+			fieldPath := path
+			if !field.Anonymous {
+				fieldPath = joinPath(path, field.Name)
+			}
+			walkLeaves(val.Field(i), fieldPath, visit)
+		}
+		if walked {
+			return
+		}
+	}
+	if path != "" {
+		visit(path, val)
+	}
+}
+
+// joinPath joins the field name to the path of its struct with a dot.
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// copyValue copies the value, with its own backing array for slices and map for maps,
+// so changes made in place are seen as changes.
+func copyValue(val reflect.Value) reflect.Value {
+	switch val.Kind() {
+	case reflect.Slice:
+		if val.IsNil() {
+			return reflect.Zero(val.Type())
+		}
+		c := reflect.MakeSlice(val.Type(), val.Len(), val.Len())
+		reflect.Copy(c, val)
+		return c
+	case reflect.Map:
+		if val.IsNil() {
+			return reflect.Zero(val.Type())
+		}
+		c := reflect.MakeMapWithSize(val.Type(), val.Len())
+		iter := val.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), iter.Value())
+		}
+		return c
+	}
+	c := reflect.New(val.Type()).Elem()
+	c.Set(val)
+	return c
+}
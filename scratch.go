@@ -0,0 +1,65 @@
+package binder
+
+import (
+	"context"
+	"sync"
+)
+
+// scratch serves the intermediate maps of a single bind from a pool, releasing them at the end of the bind.
+// A nil scratch allocates the maps as usual.
+type scratch struct {
+	free []map[string][]string
+	used []map[string][]string
+}
+
+// maxScratchMaps bounds the maps kept by a pooled scratch, so one huge request does not pin its memory.
+const maxScratchMaps = 64
+
+var scratchPool = sync.Pool{New: func() any { return &scratch{} }}
+
+type scratchContextKey struct{}
+
+// scratchFrom returns the scratch of the bind, or nil when UseScratchPool is disabled.
+func scratchFrom(ctx context.Context) *scratch {
+	s, _ := ctx.Value(scratchContextKey{}).(*scratch)
+	return s
+}
+
+// dataMap returns an empty map, reused from a previous bind when possible.
+func (s *scratch) dataMap() map[string][]string {
+	if s == nil {
+		return map[string][]string{}
+	}
+	var m map[string][]string
+	if n := len(s.free); n > 0 {
+		m, s.free = s.free[n-1], s.free[:n-1]
+	} else {
+		m = map[string][]string{}
+	}
+	s.used = append(s.used, m)
+	return m
+}
+
+// release clears the maps used by the bind and returns the scratch to the pool.
+func (s *scratch) release() {
+	for _, m := range s.used {
+		clear(m)
+		if len(s.free) < maxScratchMaps {
+			s.free = append(s.free, m)
+		}
+	}
+	clear(s.used)
+	s.used = s.used[:0]
+	scratchPool.Put(s)
+}
+
+// bindContext returns the context of the request for a bind, carrying a pooled scratch when
+// UseScratchPool is enabled, and the function releasing it at the end of the bind.
+func (b *DefaultBinder) bindContext(r BindableRequest) (context.Context, func()) {
+	ctx := RequestContext(r)
+	if !b.UseScratchPool || scratchFrom(ctx) != nil {
+		return ctx, func() {}
+	}
+	s := scratchPool.Get().(*scratch)
+	return context.WithValue(ctx, scratchContextKey{}, s), s.release
+}
@@ -0,0 +1,158 @@
+package binder
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Names of the bind sources registered on new binders, see BindSources and BindSelected.
+const (
+	SourceMetadata = "metadata" // request metadata, BindRequestMetadata
+	SourceCSRF     = "csrf"     // CSRF token, BindCSRF
+	SourceAuth     = "auth"     // Authorization credentials, BindAuth
+	SourcePath     = "path"     // path params, BindPathParams
+	SourceQuery    = "query"    // query params, BindQueryParams
+	SourceHeader   = "header"   // headers, BindHeaders
+	SourceBody     = "body"     // request body, BindBody
+	SourceClaims   = "claims"   // verified claims, BindClaims
+	SourceContext  = "ctx"      // request context values, BindContextValues
+	SourceSession  = "session"  // server-side session values, BindSession
+	SourceEnv      = "env"      // environment fallback values, BindEnv
+)
+
+// namedStep is a bind function with the name of its source, reported by the bind and used by the MergePolicy.
+type namedStep struct {
+	name string
+	fn   BindFunc
+}
+
+// RegisterBindSource registers a named bind source, i.e. `cookie`, to be selected with BindSelected or added
+// to the BindOrder. A source registered with the name of a binder source replaces it.
+func (b *DefaultBinder) RegisterBindSource(name string, fn BindFunc) {
+	if b.BindSources == nil {
+		b.BindSources = map[string]BindFunc{}
+	}
+	b.BindSources[name] = fn
+}
+
+// bindStep returns the named source: the source registered with RegisterBindSource, or else the matching
+// method of the binder, i.e. BindQueryParams for SourceQuery.
+func (b *DefaultBinder) bindStep(name string) (namedStep, bool) {
+	if fn, ok := b.BindSources[name]; ok {
+		return namedStep{name: name, fn: fn}, true
+	}
+	var fn BindFunc
+	switch name {
+	case SourceMetadata:
+		fn = b.BindRequestMetadata
+	case SourceCSRF:
+		fn = b.BindCSRF
+	case SourceAuth:
+		fn = b.BindAuth
+	case SourcePath:
+		fn = b.BindPathParams
+	case SourceQuery:
+		fn = b.BindQueryParams
+	case SourceHeader:
+		fn = b.BindHeaders
+	case SourceBody:
+		fn = b.BindBody
+	case SourceClaims:
+		fn = b.BindClaims
+	case SourceContext:
+		fn = b.BindContextValues
+	case SourceSession:
+		fn = b.BindSession
+	case SourceEnv:
+		fn = b.BindEnv
+	default:
+		return namedStep{}, false
+	}
+	return namedStep{name: name, fn: fn}, true
+}
+
+// BindSelected binds the named sources in the given order, i.e. `BindSelected(r, i, SourceQuery, SourceBody)`,
+// choosing the sources per call instead of using BindOrder. Each source can override the values
+// bound by the previous ones. Unknown names return ErrUnknownSource.
+func (b *DefaultBinder) BindSelected(r BindableRequest, i interface{}, sources ...string) error {
+	return b.bindSteps(r, i, sources)
+}
+
+// bindSteps binds the named sources in order between the BeforeBind and AfterBind hooks, resolving the
+// fields declaring their sources across them. Unknown names return ErrUnknownSource.
+func (b *DefaultBinder) bindSteps(r BindableRequest, i interface{}, sources []string) error {
+	steps := make([]namedStep, 0, len(sources))
+	for _, name := range sources {
+		step, ok := b.bindStep(name)
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownSource, name)
+		}
+		steps = append(steps, step)
+	}
+	if b.DetectConcurrentBinds && b.sourced == nil {
+		release, err := guardDestination(i)
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
+	if b.sourced == nil && b.hasSourcedFields(i) {
+		// fields declaring their sources are resolved across the steps, tracked by a copy of the binder
+		c := b.clone()
+		c.sourced = sourcedFields{}
+		return c.bindSteps(r, i, sources)
+	}
+	if err := runHooks(b.BeforeBind, r, i); err != nil {
+		return err
+	}
+	var setBy map[string]string
+	if b.report != nil || b.MergePolicy != MergeLastWins {
+		setBy = map[string]string{}
+	}
+	for _, step := range steps {
+		if cr, ok := r.(*contextRequest); ok && cr.ctx.Err() != nil {
+			return context.Cause(cr.ctx)
+		}
+		if err := b.runStep(r, i, step, setBy); err != nil {
+			return err
+		}
+	}
+	return runHooks(b.AfterBind, r, i)
+}
+
+// BindSelected binds the named sources with the *DefaultBinder, or with the matching methods of
+// other binders (path, query, header and body).
+func (b *HttpBinder) BindSelected(r *http.Request, i interface{}, sources ...string) error {
+	if db, ok := b.Binder.(*DefaultBinder); ok {
+		return db.BindSelected(NewHttpBindableRequest(r), i, sources...)
+	}
+	for _, name := range sources {
+		var fn BindFunc
+		switch name {
+		case SourcePath:
+			fn = b.Binder.BindPathParams
+		case SourceQuery:
+			fn = b.Binder.BindQueryParams
+		case SourceHeader:
+			fn = b.Binder.BindHeaders
+		case SourceBody:
+			fn = b.Binder.BindBody
+		default:
+			return fmt.Errorf("%w: %q", ErrUnknownSource, name)
+		}
+		if err := fn(NewHttpBindableRequest(r), i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BindHttpSelected binds the named sources of an http.Request, see DefaultBinder.BindSelected.
+func BindHttpSelected(r *http.Request, i interface{}, sources ...string) error {
+	b, err := requestHttpBinder(r)
+	if err != nil {
+		return err
+	}
+	return b.BindSelected(r, i, sources...)
+}
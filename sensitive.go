@@ -0,0 +1,31 @@
+package binder
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// RedactedValue stands for the values of the sensitive fields in the error messages.
+const RedactedValue = "[REDACTED]"
+
+// isSensitive reports whether the field holds secrets, i.e. `sensitive:"true"`, see SensitiveTagName.
+func (b *DefaultBinder) isSensitive(field reflect.StructField) bool {
+	if b.SensitiveTagName == "" {
+		return false
+	}
+	sensitive, _ := strconv.ParseBool(field.Tag.Get(b.SensitiveTagName))
+	return sensitive
+}
+
+// redactError replaces the error of a sensitive field with a *SensitiveFieldError, naming the field and its
+// kind only: the original error, i.e. the `parsing "hunter2"` of a conversion error, is not wrapped.
+func (b *DefaultBinder) redactError(field reflect.StructField, tag string, name string, err error) error {
+	if err == nil || !b.isSensitive(field) {
+		return err
+	}
+	typ := field.Type
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return &SensitiveFieldError{Source: tag, Field: name, Kind: typ.Kind()}
+}
@@ -0,0 +1,29 @@
+package binder
+
+import "reflect"
+
+// SessionProvider returns the values of the server-side session of a request, i.e. from gorilla/sessions or
+// SCS, bound with the session tag. An error, like a session that cannot be decoded, fails the bind.
+type SessionProvider interface {
+	Session(r BindableRequest) (map[string]interface{}, error)
+}
+
+// SessionProviderFunc adapts a function to the SessionProvider interface.
+type SessionProviderFunc func(r BindableRequest) (map[string]interface{}, error)
+
+func (f SessionProviderFunc) Session(r BindableRequest) (map[string]interface{}, error) {
+	return f(r)
+}
+
+// BindSession binds the session values to fields with the session tag, i.e. `session:"cart_id"`, like the
+// context values. Only struct destinations are bound, and nothing is bound without a SessionProvider.
+func (b *DefaultBinder) BindSession(r BindableRequest, i interface{}) error {
+	if typ := reflect.TypeOf(i); b.Session == nil || typ == nil || typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	entries, err := b.Session.Session(r)
+	if err != nil {
+		return err
+	}
+	return b.bindServerValues(r, i, entries, b.SessionTagName)
+}
@@ -0,0 +1,103 @@
+package binder
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// shadowKey identifies a field of a struct type.
+type shadowKey struct {
+	typ   reflect.Type
+	index int
+}
+
+// shadowSet holds the fields of the embedded (or untagged) structs of a destination that are not bound
+// because another field declares the same input name, see shadowedFields.
+type shadowSet map[shadowKey]bool
+
+type shadowCacheKey struct {
+	typ                reflect.Type
+	tag                string
+	bindTagName        string
+	useJSONTagFallback bool
+}
+
+type shadowResult struct {
+	shadowed  shadowSet
+	ambiguous []string
+}
+
+var shadowedTypes sync.Map // cache of the shadowed fields, by struct type, source tag and naming settings
+
+// shadowedFields returns the fields of the struct and its flattened structs (embedded or untagged) that
+// are hidden by another field with the same input name, following the depth rules of encoding/json:
+// the shallowest field wins, and fields of different structs at the same depth hide each other, their
+// names being returned as ambiguous. Fields of the same struct sharing a name are all bound.
+func (b *DefaultBinder) shadowedFields(typ reflect.Type, tag string) (shadowSet, []string) {
+	key := shadowCacheKey{typ: typ, tag: tag, bindTagName: b.BindTagName, useJSONTagFallback: b.UseJSONTagFallback}
+	if result, ok := shadowedTypes.Load(key); ok {
+		return result.(shadowResult).shadowed, result.(shadowResult).ambiguous
+	}
+
+	type candidate struct {
+		key   shadowKey
+		depth int
+	}
+	names := map[string][]candidate{}
+	visited := map[reflect.Type]bool{typ: true}
+	level := []reflect.Type{typ}
+	for depth := 0; len(level) > 0; depth++ {
+		var next []reflect.Type
+		for _, t := range level {
+			for i := 0; i < t.NumField(); i++ {
+				field := t.Field(i)
+				if !field.IsExported() || b.isExcluded(field, tag) {
+					continue
+				}
+				name := strings.TrimSuffix(b.fieldTagName(field, tag), "[]")
+				if name == "" && b.fieldSources(field) != nil {
+					name = tagName(field, b.BindTagName)
+				}
+				fieldType := field.Type
+				if field.Anonymous && fieldType.Kind() == reflect.Ptr {
+					fieldType = fieldType.Elem()
+				}
+				if name == "" {
+					// flattened like bindData does, with the keys of the parent struct
+					if fieldType.Kind() == reflect.Struct && !reflect.PointerTo(fieldType).Implements(bindUnmarshalerType) && !visited[fieldType] {
+						visited[fieldType] = true
+						next = append(next, fieldType)
+					}
+					continue
+				}
+				lower := strings.ToLower(name)
+				names[lower] = append(names[lower], candidate{key: shadowKey{typ: t, index: i}, depth: depth})
+			}
+		}
+		level = next
+	}
+
+	result := shadowResult{shadowed: shadowSet{}}
+	for name, candidates := range names {
+		// candidates are sorted by depth, the first ones are dominant
+		dominant := map[reflect.Type]bool{}
+		for _, c := range candidates {
+			if c.depth == candidates[0].depth {
+				dominant[c.key.typ] = true
+			}
+		}
+		for _, c := range candidates {
+			if c.depth > candidates[0].depth || len(dominant) > 1 {
+				result.shadowed[c.key] = true
+			}
+		}
+		if len(dominant) > 1 {
+			result.ambiguous = append(result.ambiguous, name)
+		}
+	}
+	sort.Strings(result.ambiguous)
+	shadowedTypes.Store(key, result)
+	return result.shadowed, result.ambiguous
+}
@@ -0,0 +1,127 @@
+package binder
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// sourcedFields records, during a Bind, the precedence of the source that set each field
+// declaring its sources, by field address.
+type sourcedFields map[uintptr]int
+
+var sourcedTypes sync.Map // cache of the destination types declaring sources, by type and bind tag name
+
+type sourcedTypeKey struct {
+	typ     reflect.Type
+	tagName string
+}
+
+// fieldSources returns the sources the field may be bound from, highest precedence first, as declared
+// by the `sources=` option of its bind tag, i.e. `bind:"id,sources=path>query>json"`, or nil.
+func (b *DefaultBinder) fieldSources(field reflect.StructField) []string {
+	if b.BindTagName == "" || !strings.Contains(string(field.Tag), "sources=") {
+		return nil
+	}
+	value := ""
+	tagOptions(field.Tag.Get(b.BindTagName), func(option string) bool {
+		if k, v, ok := strings.Cut(option, "="); ok && strings.TrimSpace(k) == "sources" {
+			value = strings.TrimSpace(v)
+			return true
+		}
+		return false
+	})
+	if value == "" {
+		return nil
+	}
+	sources := strings.Split(value, ">")
+	for i := range sources {
+		sources[i] = strings.TrimSpace(sources[i])
+	}
+	return sources
+}
+
+// sourceRank returns the precedence of the source among the declared sources, 0 being the highest,
+// or -1 when the field may not be bound from it. `path` stands for the param tag.
+func (b *DefaultBinder) sourceRank(sources []string, source string) int {
+	for i, s := range sources {
+		if s == source || (s == "path" && source == b.ParamTagName) {
+			return i
+		}
+	}
+	return -1
+}
+
+// bodySource returns the source name of a decoded body, `json` and `xml` for their media types
+// and structured syntax suffixes (`application/vnd.acme+json`), or the media type itself.
+func bodySource(mediaType string) string {
+	switch {
+	case mediaType == MIMEApplicationJSON || strings.HasSuffix(mediaType, "+json"):
+		return "json"
+	case mediaType == MIMEApplicationXML || mediaType == MIMETextXML || strings.HasSuffix(mediaType, "+xml"):
+		return "xml"
+	}
+	return mediaType
+}
+
+// claimField reports whether the source of the given precedence may set the field, that is no source
+// of higher precedence set it earlier in the Bind, recording the source when the input is supplied.
+func (b *DefaultBinder) claimField(field reflect.Value, rank int, supplied bool) bool {
+	if b.sourced == nil {
+		return true
+	}
+	if prev, ok := b.sourced[field.UnsafeAddr()]; ok && prev < rank {
+		return false
+	}
+	if supplied {
+		b.sourced[field.UnsafeAddr()] = rank
+	}
+	return true
+}
+
+// hasSourcedFields reports whether the destination type has fields declaring their sources.
+func (b *DefaultBinder) hasSourcedFields(i interface{}) bool {
+	if i == nil || b.BindTagName == "" {
+		return false
+	}
+	key := sourcedTypeKey{typ: reflect.TypeOf(i), tagName: b.BindTagName}
+	if has, ok := sourcedTypes.Load(key); ok {
+		return has.(bool)
+	}
+	has := hasMatchingFields(key.typ, func(field reflect.StructField) bool {
+		return b.fieldSources(field) != nil
+	}, map[reflect.Type]bool{})
+	sourcedTypes.Store(key, has)
+	return has
+}
+
+// snapshotSourced saves the fields declaring their sources before decoding a body, returning a function
+// restoring the ones the body may not set: the body is not among their sources, or a source of higher
+// precedence set them earlier in the Bind.
+func (b *DefaultBinder) snapshotSourced(i interface{}, source string) func() {
+	if !b.hasSourcedFields(i) {
+		return func() {}
+	}
+	match := func(field reflect.StructField) bool {
+		return b.fieldSources(field) != nil
+	}
+	val := reflect.ValueOf(i)
+	saved := map[string]reflect.Value{}
+	walkFields(val.Elem(), "", match, func(path string, field reflect.Value, _ reflect.StructField) {
+		value := reflect.New(field.Type()).Elem()
+		value.Set(field)
+		saved[path] = value
+	})
+	return func() {
+		walkFields(val.Elem(), "", match, func(path string, field reflect.Value, typeField reflect.StructField) {
+			value, ok := saved[path]
+			if !ok {
+				value = reflect.Zero(field.Type())
+			}
+			changed := !reflect.DeepEqual(field.Interface(), value.Interface())
+			if rank := b.sourceRank(b.fieldSources(typeField), source); rank < 0 || !b.claimField(field, rank, changed) {
+				field.Set(value)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package binder
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ByteSize is a size in bytes bound from literals like `512`, `10MB` or `512KiB`.
+type ByteSize int64
+
+// Rate is a number of events per second bound from literals like `100rps`, `600/m` or `10rph`.
+type Rate float64
+
+// byteSizeUnits maps the supported units (lower case) to their size in bytes.
+// KB, MB... are decimal (SI) units while KiB, MiB... are binary (IEC) units.
+var byteSizeUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"pb":  1e15,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"pib": 1 << 50,
+}
+
+// rateUnits maps the supported units (lower case) to their length in seconds.
+var rateUnits = map[string]float64{
+	"":    1,
+	"rps": 1,
+	"/s":  1,
+	"rpm": 60,
+	"/m":  60,
+	"rph": 3600,
+	"/h":  3600,
+}
+
+// splitUnit splits a literal like `10MB` into its number and lower case unit.
+func splitUnit(value string) (float64, string, error) {
+	value = strings.TrimSpace(value)
+	i := strings.IndexFunc(value, func(r rune) bool {
+		return !unicode.IsDigit(r) && r != '.' && r != '+' && r != '-'
+	})
+	if i < 0 {
+		i = len(value)
+	}
+	n, err := strconv.ParseFloat(value[:i], 64)
+	if err != nil {
+		return 0, "", err
+	}
+	return n, strings.ToLower(strings.TrimSpace(value[i:])), nil
+}
+
+// ParseByteSize parses a byte size literal like `512`, `10MB` or `1.5GiB`.
+func ParseByteSize(value string) (int64, error) {
+	n, unit, err := splitUnit(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q", value)
+	}
+	multiplier, ok := byteSizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid byte size unit %q", unit)
+	}
+	size := n * multiplier
+	if size < 0 || size >= math.MaxInt64 || size != math.Trunc(size) {
+		return 0, fmt.Errorf("invalid byte size %q", value)
+	}
+	return int64(size), nil
+}
+
+// ParseRate parses a rate literal like `100rps`, `600/m` or `10rph` into events per second.
+// A bare number is a rate per second.
+func ParseRate(value string) (float64, error) {
+	n, unit, err := splitUnit(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid rate %q", value)
+	}
+	seconds, ok := rateUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid rate unit %q", unit)
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("invalid rate %q", value)
+	}
+	return n / seconds, nil
+}
+
+// ConvertByteSize converts a byte size literal into an integer field.
+func ConvertByteSize(values []string, dst reflect.Value, _ reflect.StructField) error {
+	if len(values) == 0 || values[0] == "" {
+		return nil
+	}
+	size, err := ParseByteSize(values[0])
+	if err != nil {
+		return err
+	}
+	switch dst.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if dst.OverflowInt(size) {
+			return fmt.Errorf("byte size %q overflows %s", values[0], dst.Type())
+		}
+		dst.SetInt(size)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if dst.OverflowUint(uint64(size)) {
+			return fmt.Errorf("byte size %q overflows %s", values[0], dst.Type())
+		}
+		dst.SetUint(uint64(size))
+	default:
+		return fmt.Errorf("byte size converter cannot bind to %s", dst.Type())
+	}
+	return nil
+}
+
+// ConvertRate converts a rate literal into a float field holding events per second.
+func ConvertRate(values []string, dst reflect.Value, _ reflect.StructField) error {
+	if len(values) == 0 || values[0] == "" {
+		return nil
+	}
+	rate, err := ParseRate(values[0])
+	if err != nil {
+		return err
+	}
+	switch dst.Kind() {
+	case reflect.Float32, reflect.Float64:
+		dst.SetFloat(rate)
+	default:
+		return fmt.Errorf("rate converter cannot bind to %s", dst.Type())
+	}
+	return nil
+}
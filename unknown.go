@@ -0,0 +1,133 @@
+package binder
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+)
+
+var jsonRawMessageType = reflect.TypeOf(json.RawMessage{})
+
+// unknownFieldsField returns the catch-all field of the struct destination, a map with string keys
+// tagged with the `,unknown` option, i.e. `json:"-" bind:",unknown"`.
+func unknownFieldsField(i interface{}) (reflect.Value, bool) {
+	val := reflect.ValueOf(i)
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	val = val.Elem()
+	typ := val.Type()
+	for index := 0; index < typ.NumField(); index++ {
+		field := typ.Field(index)
+		if !field.IsExported() || field.Type.Kind() != reflect.Map || field.Type.Key().Kind() != reflect.String {
+			continue
+		}
+		if strings.Contains(string(field.Tag), "unknown") && eachTagValue(field.Tag, func(value string) bool {
+			return tagOptions(value, func(option string) bool {
+				return option == "unknown"
+			})
+		}) {
+			return val.Field(index), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// captureUnknownJSON records the JSON body read by the deserializer when the destination has a catch-all
+// field, returning a function collecting the keys not matching any other field into it after decoding.
+func captureUnknownJSON(r BindableRequest, mediaType string, i interface{}) func() error {
+	field, ok := unknownFieldsField(i)
+	if !ok {
+		return func() error { return nil }
+	}
+	body, ok := teeJSONBody(r, mediaType)
+	if !ok {
+		return func() error { return nil }
+	}
+
+	return func() error {
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(body.Bytes(), &values); err != nil {
+			// not an object, the deserializer already reported the errors
+			return nil
+		}
+		known := map[string]bool{}
+		jsonFieldNames(reflect.TypeOf(i).Elem(), known, map[reflect.Type]bool{})
+		mapType := field.Type()
+		unknown := reflect.Zero(mapType)
+		for key, raw := range values {
+			if known[strings.ToLower(key)] {
+				continue
+			}
+			if unknown.IsNil() {
+				unknown = reflect.MakeMap(mapType)
+			}
+			elem := reflect.New(mapType.Elem()).Elem()
+			if mapType.Elem() == jsonRawMessageType {
+				elem.SetBytes(raw)
+			} else if err := json.Unmarshal(raw, elem.Addr().Interface()); err != nil {
+				return &MapValueError{Key: key, Type: mapType.Elem(), Err: err}
+			}
+			unknown.SetMapIndex(reflect.ValueOf(key).Convert(mapType.Key()), elem)
+		}
+		field.Set(unknown)
+		return nil
+	}
+}
+
+// teeJSONBody records the JSON body read by the deserializer into the returned buffer.
+// It reports false for other media types, or when the body is not read through the binder.
+func teeJSONBody(r BindableRequest, mediaType string) (*bytes.Buffer, bool) {
+	mr, ok := r.(*mediaTypeRequest)
+	if !ok || bodySource(mediaType) != "json" {
+		return nil, false
+	}
+	var body bytes.Buffer
+	switch reader := mr.GetBody().(type) {
+	case nil:
+		return nil, false
+	case *contentLengthReader:
+		// keep checking the body against its Content-Length
+		reader.reader = io.TeeReader(reader.reader, &body)
+	default:
+		// the body was replaced, i.e. by a body processor
+		mr.body = io.TeeReader(reader, &body)
+	}
+	return &body, true
+}
+
+// jsonFieldNames adds the lower case JSON names of the fields of the struct type to known, flattening
+// the embedded structs like encoding/json does.
+func jsonFieldNames(typ reflect.Type, known map[string]bool, seen map[reflect.Type]bool) {
+	if seen[typ] {
+		return
+	}
+	seen[typ] = true
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Tag.Get("json") == "-" {
+			continue
+		}
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if tagName(field, "json") == "" && field.Anonymous && fieldType.Kind() == reflect.Struct {
+			jsonFieldNames(fieldType, known, seen)
+			continue
+		}
+		if field.IsExported() {
+			known[strings.ToLower(jsonFieldName(field))] = true
+		}
+	}
+}
+
+// jsonFieldName returns the name of the field in JSON objects, its json tag name or else its name.
+func jsonFieldName(field reflect.StructField) string {
+	if name := tagName(field, "json"); name != "" {
+		return name
+	}
+	return field.Name
+}
@@ -0,0 +1,35 @@
+package binder
+
+import "reflect"
+
+// unsettableFieldError returns an *UnsettableFieldError when StrictFields is enabled and the field,
+// which the binder cannot set, declares an input for the source, nil otherwise.
+func (b *DefaultBinder) unsettableFieldError(typeField reflect.StructField, tag string) error {
+	if !b.StrictFields || !b.declaresInput(typeField, tag, map[reflect.Type]bool{}) {
+		return nil
+	}
+	// embedded pointers are allocated before binding, so only unexported fields remain unsettable
+	return &UnsettableFieldError{Source: tag, Field: typeField.Name, Reason: "unexported"}
+}
+
+// declaresInput reports whether the field is tagged for the source, or is an untagged struct
+// (usually embedded) with such fields.
+func (b *DefaultBinder) declaresInput(typeField reflect.StructField, tag string, seen map[reflect.Type]bool) bool {
+	if name := tagName(typeField, tag); name != "" {
+		return name != "-"
+	}
+	typ := typeField.Type
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || seen[typ] {
+		return false
+	}
+	seen[typ] = true
+	for i := 0; i < typ.NumField(); i++ {
+		if b.declaresInput(typ.Field(i), tag, seen) {
+			return true
+		}
+	}
+	return false
+}
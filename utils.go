@@ -1,37 +1,191 @@
 package binder
 
 import (
+	"context"
+	"database/sql"
 	"encoding"
+	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"fmt"
+	"math"
 	"mime/multipart"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 )
 
+// bracketRegexp matches any [key] group, used to convert nested brackets to dot notation
+var bracketRegexp = regexp.MustCompile(`\[([^\[\]]*)\]`)
+
 // getPrefixedFieldNames returns a map of field names that are prefixed with the given prefix.
+// The remaining key is converted to dot notation: `items[0].name`, `items[0][name]` and `items.0.name`
+// are all `0.name` for the `items` prefix. The first bracket must be matched by the matcher.
+// Empty brackets are kept as Rack-style append elements, i.e. `items[][name]` is `[].name`.
 func getPrefixedFieldNames(prefix string, keys []string, matcher *regexp.Regexp, deepSeparator string) map[string]string {
 	result := map[string]string{}
 	for _, k := range keys {
-		if strings.HasPrefix(k, prefix) {
-			if strings.HasPrefix(k, prefix+deepSeparator) {
-				result[k] = strings.TrimPrefix(k, prefix+deepSeparator) // dot notation
-			} else if matches := matcher.FindAllStringSubmatch(k, -1); len(matches) > 0 {
-				if len(matches) == 0 {
-					continue
-				}
-				finalValue := []string{}
-				// convert all the matches to dot notation (it should be faster than using check for each match)
-				for _, match := range matches {
-					val := match[1]
-					if val == "" {
-						break
-					}
-					finalValue = append(finalValue, val)
+		if name, ok := prefixedFieldName(prefix, k, matcher, deepSeparator); ok {
+			result[k] = name
+		}
+	}
+	return result
+}
+
+// prefixedFieldName returns the key without the prefix in dot notation, see getPrefixedFieldNames.
+func prefixedFieldName(prefix string, k string, matcher *regexp.Regexp, deepSeparator string) (string, bool) {
+	rest, ok := strings.CutPrefix(k, prefix)
+	if !ok {
+		return "", false
+	}
+	if strings.HasPrefix(rest, deepSeparator) {
+		return strings.TrimPrefix(rest, deepSeparator), true // dot notation
+	}
+	if !strings.HasPrefix(rest, "[") {
+		// a different key sharing the prefix, i.e. `username` for `user`
+		return "", false
+	}
+	if after, ok := strings.CutPrefix(rest, "[]"); ok {
+		// `items[]` is bound as the values of `items`, `items[][name]` as an append element
+		if after == "" || (!strings.HasPrefix(after, "[") && !strings.HasPrefix(after, deepSeparator)) {
+			return "", false
+		}
+		return "[]" + bracketsToDots(after, deepSeparator), true
+	}
+	if loc := matcher.FindStringIndex(rest); loc == nil || loc[0] != 0 {
+		return "", false
+	}
+	return strings.TrimPrefix(bracketsToDots(rest, deepSeparator), deepSeparator), true
+}
+
+// bracketsToDots converts the brackets of the key to dot notation, keeping the empty brackets
+// of append keys, i.e. `[a][b][]` is `.a.b[]`.
+func bracketsToDots(key string, deepSeparator string) string {
+	if !strings.Contains(key, "[") {
+		return key
+	}
+	var sb strings.Builder
+	sb.Grow(len(key) + len(deepSeparator)*strings.Count(key, "["))
+	for i := 0; i < len(key); {
+		if key[i] == '[' {
+			// a bracket without nested brackets, like bracketRegexp
+			if j := strings.IndexAny(key[i+1:], "[]"); j >= 0 && key[i+1+j] == ']' {
+				if inner := key[i+1 : i+1+j]; inner == "" {
+					sb.WriteString("[]")
+				} else {
+					sb.WriteString(deepSeparator)
+					sb.WriteString(inner)
 				}
-				result[k] = strings.Join(finalValue, deepSeparator)
+				i += j + 2
+				continue
+			}
+		}
+		sb.WriteByte(key[i])
+		i++
+	}
+	return sb.String()
+}
+
+// lookupInput returns the key and values of the named input.
+// Go json.Unmarshal supports case-insensitive binding.  However the
+// url params are bound case-sensitive which is inconsistent.  To
+// fix this we must check all of the map values in a
+// case-insensitive search, unless the binder matches keys exactly (CompatV2).
+func lookupInput(data map[string][]string, name string, caseInsensitive bool) (string, []string, bool) {
+	if values, ok := data[name]; ok {
+		return name, values, true
+	}
+	if !caseInsensitive {
+		return name, nil, false
+	}
+	for k, v := range data {
+		if strings.EqualFold(k, name) {
+			return k, v, true
+		}
+	}
+	return name, nil, false
+}
+
+// lookupAppendInput returns the values of the named input merged with the values of its PHP-style
+// append key, i.e. `tags` and `tags[]`.
+func lookupAppendInput(data map[string][]string, name string, caseInsensitive bool) (string, []string, bool) {
+	key, values, ok := lookupInput(data, name, caseInsensitive)
+	appendKey, appendValues, appendOk := lookupInput(data, name+"[]", caseInsensitive)
+	if !appendOk {
+		return key, values, ok
+	}
+	if !ok {
+		return appendKey, appendValues, true
+	}
+	return key, append(append([]string{}, values...), appendValues...), true
+}
+
+// hasInput reports whether the named input exists, see lookupInput.
+func hasInput(data map[string][]string, name string, caseInsensitive bool) bool {
+	_, _, ok := lookupInput(data, name, caseInsensitive)
+	return ok
+}
+
+// normalizeValues applies the TrimStrings setting and the `,trim`, `,lower` and `,upper` tag options
+// of the field to a copy of the values.
+func (b *DefaultBinder) normalizeValues(field reflect.StructField, tag string, values []string) []string {
+	trim := b.TrimStrings || hasTagFlag(field, tag, "trim")
+	lower := hasTagFlag(field, tag, "lower")
+	upper := hasTagFlag(field, tag, "upper")
+	if !trim && !lower && !upper {
+		return values
+	}
+	result := make([]string, len(values))
+	for i, value := range values {
+		if trim {
+			value = strings.TrimSpace(value)
+		}
+		if lower {
+			value = strings.ToLower(value)
+		} else if upper {
+			value = strings.ToUpper(value)
+		}
+		result[i] = value
+	}
+	return result
+}
+
+// valuesDelimiter returns the delimiter used to split the values of a slice field: set with the `,split`
+// (comma) or `,delim=` options, or a comma for headers when SplitHeaderValues is enabled.
+// The delimiter can be a literal or one of the OpenAPI style names `comma`, `pipe` and `space`.
+func (b *DefaultBinder) valuesDelimiter(field reflect.StructField, tag string) string {
+	typ := field.Type
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Slice {
+		return ""
+	}
+	delimiter := TagOption(field, "delim")
+	switch delimiter {
+	case "comma":
+		return ","
+	case "pipe":
+		return "|"
+	case "space":
+		return " "
+	case "":
+		if hasTagFlag(field, tag, "split") || (tag == b.HeaderTagName && b.SplitHeaderValues) {
+			return ","
+		}
+	}
+	return delimiter
+}
+
+// splitValues splits each value on the separator, trimming spaces and dropping empty parts.
+func splitValues(values []string, separator string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		for _, part := range strings.Split(value, separator) {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
 			}
 		}
 	}
@@ -39,45 +193,106 @@ func getPrefixedFieldNames(prefix string, keys []string, matcher *regexp.Regexp,
 }
 
 // trimData trims the data map to only include keys that start with the given prefix.
-func trimData(prefix string, data map[string][]string, matcher *regexp.Regexp, deepSeparator string) map[string][]string {
-	result := map[string][]string{}
-	keys := []string{}
-	for key := range data {
-		keys = append(keys, key)
+// The result map is taken from the scratch, which may be nil.
+func trimData(s *scratch, prefix string, data map[string][]string, matcher *regexp.Regexp, deepSeparator string) map[string][]string {
+	if len(data) == 0 {
+		return nil
 	}
-	fieldNames := getPrefixedFieldNames(prefix, keys, matcher, deepSeparator)
-	for k, v := range fieldNames {
-		result[v] = data[k]
+	result := s.dataMap()
+	for k, v := range data {
+		if name, ok := prefixedFieldName(prefix, k, matcher, deepSeparator); ok {
+			result[name] = v
+		}
 	}
 	return result
 }
 
 // trimFileFields trims the files map to only include keys that start with the given prefix.
 func trimFileFields(prefix string, files map[string][]*multipart.FileHeader, matcher *regexp.Regexp, deepSeparator string) map[string][]*multipart.FileHeader {
+	if len(files) == 0 {
+		return nil
+	}
 	result := map[string][]*multipart.FileHeader{}
-	keys := []string{}
-	for key := range files {
-		keys = append(keys, key)
+	for k, v := range files {
+		if name, ok := prefixedFieldName(prefix, k, matcher, deepSeparator); ok {
+			result[name] = v
+		}
+	}
+	return result
+}
+
+// convertMapKey converts the key to the key type of a map, with the type converters, the param
+// unmarshalers or the built-in kinds. Conversion errors are returned as *MapKeyError.
+func (b *DefaultBinder) convertMapKey(key string, keyType reflect.Type) (reflect.Value, error) {
+	if keyType.Kind() == reflect.String {
+		return reflect.ValueOf(key).Convert(keyType), nil
 	}
-	fieldNames := getPrefixedFieldNames(prefix, keys, matcher, deepSeparator)
-	for k, v := range fieldNames {
-		result[v] = files[k]
+	value := reflect.New(keyType).Elem()
+	ok, err := b.convertType([]string{key}, value, reflect.StructField{Name: key, Type: keyType})
+	if !ok {
+		ok, err = unmarshalInputToField(keyType.Kind(), key, value)
+	}
+	if !ok {
+		if isScalarKind(keyType.Kind()) {
+			err = b.setWithProperType(keyType.Kind(), key, value)
+		} else {
+			err = errors.New("unsupported map key type")
+		}
 	}
-	for k, v := range fieldNames {
-		result[v] = files[k]
+	if err != nil {
+		return reflect.Value{}, &MapKeyError{Key: key, Type: keyType, Err: err}
 	}
-	return result
+	return value, nil
+}
+
+// isNumberKind reports whether the kind is an integer or a float kind.
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// spaceAsPlusSign restores the plus sign of a number sent unencoded in a query or form value, i.e.
+// `?delta=+5`, which URL decoding turns into a leading space.
+func spaceAsPlusSign(value string) string {
+	if len(value) > 1 && value[0] == ' ' && (value[1] >= '0' && value[1] <= '9' || value[1] == '.') {
+		return "+" + value[1:]
+	}
+	return value
+}
+
+// isScalarKind reports whether values of the kind are converted by setWithProperType, strings aside.
+func isScalarKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Bool, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
 }
 
-func setWithProperType(valueKind reflect.Kind, val string, structField reflect.Value) error {
+func (b *DefaultBinder) setWithProperType(valueKind reflect.Kind, val string, structField reflect.Value) error {
 	// But also call it here, in case we're dealing with an array of BindUnmarshalers
 	if ok, err := unmarshalInputToField(valueKind, val, structField); ok {
 		return err
 	}
+	if val == "" && isScalarKind(valueKind) && b.compatLevel() >= CompatV2 {
+		// empty values no longer mean zero
+		return nil
+	}
+
+	if b.SpaceAsPlusSign && isNumberKind(valueKind) {
+		val = spaceAsPlusSign(val)
+	}
 
 	switch valueKind {
 	case reflect.Ptr:
-		return setWithProperType(structField.Elem().Kind(), val, structField.Elem())
+		return b.setWithProperType(structField.Elem().Kind(), val, structField.Elem())
 	case reflect.Int:
 		return setIntField(val, 0, structField)
 	case reflect.Int8:
@@ -99,19 +314,63 @@ func setWithProperType(valueKind reflect.Kind, val string, structField reflect.V
 	case reflect.Uint64:
 		return setUintField(val, 64, structField)
 	case reflect.Bool:
-		return setBoolField(val, structField)
+		return b.setBoolField(val, structField)
 	case reflect.Float32:
-		return setFloatField(val, 32, structField)
+		return b.setFloatField(val, 32, structField)
 	case reflect.Float64:
-		return setFloatField(val, 64, structField)
+		return b.setFloatField(val, 64, structField)
 	case reflect.String:
 		structField.SetString(val)
 	default:
-		return errors.New("unknown type")
+		// kinds without a builtin conversion, like complex numbers, can still have a registered converter
+		if ok, err := b.convertType([]string{val}, structField, reflect.StructField{Type: structField.Type()}); ok {
+			return err
+		}
+		return &UnsupportedFieldTypeError{Type: structField.Type()}
 	}
 	return nil
 }
 
+// unsupportedFieldType sets the field of an *UnsupportedFieldTypeError returned by setWithProperType,
+// which only knows the value it sets.
+func unsupportedFieldType(err error, field string) error {
+	var typeErr *UnsupportedFieldTypeError
+	if errors.As(err, &typeErr) && typeErr.Field == "" {
+		typeErr.Field = field
+	}
+	return err
+}
+
+func unmarshalKeyInputsToField(valueKind reflect.Kind, key string, values []string, field reflect.Value) (bool, error) {
+	if valueKind == reflect.Ptr {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		field = field.Elem()
+	}
+
+	unmarshaler, ok := field.Addr().Interface().(BindKeyUnmarshaler)
+	if !ok {
+		return false, nil
+	}
+	return true, unmarshaler.UnmarshalParamWithKey(key, values)
+}
+
+func unmarshalContextInputToField(ctx context.Context, valueKind reflect.Kind, val string, field reflect.Value) (bool, error) {
+	if valueKind == reflect.Ptr {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		field = field.Elem()
+	}
+
+	unmarshaler, ok := field.Addr().Interface().(BindContextUnmarshaler)
+	if !ok {
+		return false, nil
+	}
+	return true, unmarshaler.UnmarshalParamContext(ctx, val)
+}
+
 func unmarshalInputsToField(valueKind reflect.Kind, values []string, field reflect.Value) (bool, error) {
 	if valueKind == reflect.Ptr {
 		if field.IsNil() {
@@ -147,6 +406,59 @@ func unmarshalInputToField(valueKind reflect.Kind, val string, field reflect.Val
 	return false, nil
 }
 
+// isJSONUnmarshaler reports whether the field implements json.Unmarshaler but none of the param unmarshalers.
+func isJSONUnmarshaler(field reflect.Value) bool {
+	switch field.Addr().Interface().(type) {
+	case BindUnmarshaler, BindKeyUnmarshaler, BindContextUnmarshaler, bindMultipleUnmarshaler, encoding.TextUnmarshaler:
+		return false
+	case json.Unmarshaler:
+		return true
+	}
+	return false
+}
+
+// unmarshalJSONInputToField binds the value to types implementing json.Unmarshaler, so params get the same
+// semantics as the JSON body. JSON objects and arrays are passed as is, any other value is passed as a JSON
+// string, whatever the client typed: `?id=123` and `?name=null` are the strings "123" and "null".
+func unmarshalJSONInputToField(valueKind reflect.Kind, val string, field reflect.Value) (bool, error) {
+	if valueKind == reflect.Ptr {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		field = field.Elem()
+	}
+
+	unmarshaler, ok := field.Addr().Interface().(json.Unmarshaler)
+	if !ok {
+		return false, nil
+	}
+	raw := []byte(val)
+	if len(raw) == 0 || (raw[0] != '{' && raw[0] != '[') || !json.Valid(raw) {
+		raw, _ = json.Marshal(val)
+	}
+	return true, unmarshaler.UnmarshalJSON(raw)
+}
+
+// scanInputToField binds the value to types implementing sql.Scanner, like sql.NullString.
+// An empty value is scanned as NULL.
+func scanInputToField(valueKind reflect.Kind, val string, field reflect.Value) (bool, error) {
+	if valueKind == reflect.Ptr {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		field = field.Elem()
+	}
+
+	scanner, ok := field.Addr().Interface().(sql.Scanner)
+	if !ok {
+		return false, nil
+	}
+	if val == "" {
+		return true, scanner.Scan(nil)
+	}
+	return true, scanner.Scan(val)
+}
+
 func setIntField(value string, bitSize int, field reflect.Value) error {
 	if value == "" {
 		value = "0"
@@ -162,17 +474,26 @@ func setUintField(value string, bitSize int, field reflect.Value) error {
 	if value == "" {
 		value = "0"
 	}
-	uintVal, err := strconv.ParseUint(value, 10, bitSize)
+	// strconv.ParseUint rejects the plus sign ParseInt accepts
+	uintVal, err := strconv.ParseUint(strings.TrimPrefix(value, "+"), 10, bitSize)
 	if err == nil {
 		field.SetUint(uintVal)
 	}
 	return err
 }
 
-func setBoolField(value string, field reflect.Value) error {
+func (b *DefaultBinder) setBoolField(value string, field reflect.Value) error {
 	if value == "" {
 		value = "false"
 	}
+	if b.LenientBool {
+		switch strings.ToLower(value) {
+		case "on", "yes", "y":
+			value = "true"
+		case "off", "no", "n":
+			value = "false"
+		}
+	}
 	boolVal, err := strconv.ParseBool(value)
 	if err == nil {
 		field.SetBool(boolVal)
@@ -180,17 +501,38 @@ func setBoolField(value string, field reflect.Value) error {
 	return err
 }
 
-func setFloatField(value string, bitSize int, field reflect.Value) error {
+func (b *DefaultBinder) setFloatField(value string, bitSize int, field reflect.Value) error {
 	if value == "" {
 		value = "0.0"
 	}
+	if !b.AllowFloatExponent && hasFloatExponent(value) {
+		return fmt.Errorf("%w: %q", ErrFloatExponent, value)
+	}
 	floatVal, err := strconv.ParseFloat(value, bitSize)
-	if err == nil {
-		field.SetFloat(floatVal)
+	if err != nil {
+		return err
 	}
-	return err
+	if !b.AllowFloatNonFinite && (math.IsInf(floatVal, 0) || math.IsNaN(floatVal)) {
+		return fmt.Errorf("%w: %q", ErrFloatNonFinite, value)
+	}
+	field.SetFloat(floatVal)
+	return nil
+}
+
+// hasFloatExponent reports whether the value is written in scientific notation (`1e9`)
+// or as a hexadecimal float (`0x1p-2`), which always carries a binary exponent.
+func hasFloatExponent(value string) bool {
+	value = strings.ToLower(strings.TrimLeft(value, "+-"))
+	if strings.HasPrefix(value, "0x") {
+		return true
+	}
+	return strings.ContainsRune(value, 'e')
 }
 
+var xmlNameType = reflect.TypeOf(xml.Name{})
+
+var bindUnmarshalerType = reflect.TypeOf((*BindUnmarshaler)(nil)).Elem()
+
 var (
 	// NOT supported by bind as you can NOT check easily empty struct being actual file or not
 	multipartFileHeaderType = reflect.TypeOf(multipart.FileHeader{})
@@ -198,13 +540,21 @@ var (
 	multipartFileHeaderPointerType      = reflect.TypeOf(&multipart.FileHeader{})
 	multipartFileHeaderSliceType        = reflect.TypeOf([]multipart.FileHeader(nil))
 	multipartFileHeaderPointerSliceType = reflect.TypeOf([]*multipart.FileHeader(nil))
+	uploadedFileType                    = reflect.TypeOf(UploadedFile{})
+	uploadedFilePointerType             = reflect.TypeOf(&UploadedFile{})
+	uploadedFileSliceType               = reflect.TypeOf([]UploadedFile(nil))
+	uploadedFilePointerSliceType        = reflect.TypeOf([]*UploadedFile(nil))
 )
 
 func isFieldMultipartFile(field reflect.Type) (bool, error) {
 	switch field {
 	case multipartFileHeaderPointerType,
 		multipartFileHeaderSliceType,
-		multipartFileHeaderPointerSliceType:
+		multipartFileHeaderPointerSliceType,
+		uploadedFileType,
+		uploadedFilePointerType,
+		uploadedFileSliceType,
+		uploadedFilePointerSliceType:
 		return true, nil
 	case multipartFileHeaderType:
 		return true, errors.New("binding to multipart.FileHeader struct is not supported, use pointer to struct")
@@ -214,7 +564,7 @@ func isFieldMultipartFile(field reflect.Type) (bool, error) {
 }
 
 func setMultipartFileHeaderTypes(structField reflect.Value, inputFieldName string, files map[string][]*multipart.FileHeader) bool {
-	fileHeaders := files[inputFieldName]
+	fileHeaders := fieldFileHeaders(inputFieldName, files)
 	if len(fileHeaders) == 0 {
 		return false
 	}
@@ -231,6 +581,19 @@ func setMultipartFileHeaderTypes(structField reflect.Value, inputFieldName strin
 		structField.Set(reflect.ValueOf(headers))
 	case multipartFileHeaderPointerType:
 		structField.Set(reflect.ValueOf(fileHeaders[0]))
+	case uploadedFileSliceType:
+		structField.Set(reflect.ValueOf(uploadedFiles(inputFieldName, fileHeaders)))
+	case uploadedFilePointerSliceType:
+		uploaded := uploadedFiles(inputFieldName, fileHeaders)
+		pointers := make([]*UploadedFile, len(uploaded))
+		for i := range uploaded {
+			pointers[i] = &uploaded[i]
+		}
+		structField.Set(reflect.ValueOf(pointers))
+	case uploadedFileType:
+		structField.Set(reflect.ValueOf(uploadedFiles(inputFieldName, fileHeaders[:1])[0]))
+	case uploadedFilePointerType:
+		structField.Set(reflect.ValueOf(&uploadedFiles(inputFieldName, fileHeaders[:1])[0]))
 	default:
 		result = false
 	}
@@ -238,35 +601,207 @@ func setMultipartFileHeaderTypes(structField reflect.Value, inputFieldName strin
 	return result
 }
 
-func handleArrayValues(structValue reflect.Value, structFieldKind reflect.Kind, values map[string][]string, _ map[string][]*multipart.FileHeader, inputFieldName string, maxArraySize int) error {
-	if structFieldKind == reflect.Slice {
-		for k, v := range values {
-			intIndex, err := strconv.Atoi(k)
-			if err != nil {
-				return fmt.Errorf("invalid array index %s", k)
-			}
+// handleArrayValues binds indexed values (`items[0]`, `items.0`) to a slice. Keys with a remaining path
+// (`items[0].name`, or `items[][name]` to append an element) are bound to struct or map elements.
+// Scalar elements are normalized with the options of the field, see normalizeValues.
+func (b *DefaultBinder) handleArrayValues(ctx context.Context, field reflect.StructField, structValue reflect.Value, structFieldKind reflect.Kind, values map[string][]string, files map[string][]*multipart.FileHeader, inputFieldName string, tag string, maxArraySize int) error {
+	if structFieldKind != reflect.Slice || (len(values) == 0 && len(files) == 0) {
+		return nil
+	}
 
-			if intIndex > maxArraySize {
-				return fmt.Errorf("%s array size exceeds the maximum allowed size of %d", inputFieldName, maxArraySize)
+	// group the values by element index
+	scalars := map[int][]string{}
+	elementValues := map[int]map[string][]string{}
+	elementFiles := map[int]map[string][]*multipart.FileHeader{}
+	maxIndex := -1
+	truncated := false
+	// exceedsMax reports whether the element of the index exceeds the max array size, the extra
+	// elements being dropped under ArraySizeTruncate
+	exceedsMax := func(intIndex int) (bool, error) {
+		if intIndex < maxArraySize {
+			return false, nil
+		}
+		if b.ArraySizePolicy == ArraySizeTruncate {
+			truncated = true
+			return true, nil
+		}
+		return true, fmt.Errorf("%s array size exceeds the maximum allowed size of %d", inputFieldName, maxArraySize)
+	}
+	parseIndex := func(k string) (int, string, bool, error) {
+		index, path, _ := strings.Cut(k, b.DeepObjectSeparator)
+		intIndex, err := strconv.Atoi(index)
+		if err != nil || intIndex < 0 {
+			return 0, "", false, fmt.Errorf("invalid array index %s", index)
+		}
+		if exceeds, err := exceedsMax(intIndex); exceeds {
+			return 0, "", false, err
+		}
+		if intIndex > maxIndex {
+			maxIndex = intIndex
+		}
+		return intIndex, path, true, nil
+	}
+	// append elements (`items[][name]`) follow the indexed ones, the nth value of each key
+	// belonging to the nth element: unlike Rack, the order of different keys is unknown here
+	appendValues := map[string][]string{}
+	appendFiles := map[string][]*multipart.FileHeader{}
+	for k, v := range values {
+		if path, ok := strings.CutPrefix(k, "[]"+b.DeepObjectSeparator); ok {
+			appendValues[path] = v
+			continue
+		}
+		intIndex, path, ok, err := parseIndex(k)
+		if err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+		if path == "" {
+			scalars[intIndex] = v
+			continue
+		}
+		if elementValues[intIndex] == nil {
+			elementValues[intIndex] = scratchFrom(ctx).dataMap()
+		}
+		elementValues[intIndex][path] = v
+	}
+	for k, v := range files {
+		if path, ok := strings.CutPrefix(k, "[]"+b.DeepObjectSeparator); ok {
+			appendFiles[path] = v
+			continue
+		}
+		intIndex, path, ok, err := parseIndex(k)
+		if err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+		if path == "" {
+			continue
+		}
+		if elementFiles[intIndex] == nil {
+			elementFiles[intIndex] = map[string][]*multipart.FileHeader{}
+		}
+		elementFiles[intIndex][path] = v
+	}
+	if len(appendValues) > 0 || len(appendFiles) > 0 {
+		base := maxIndex + 1
+		appendIndex := func(i int) (int, bool, error) {
+			if exceeds, err := exceedsMax(base + i); exceeds {
+				return 0, false, err
+			}
+			if base+i > maxIndex {
+				maxIndex = base + i
+			}
+			return base + i, true, nil
+		}
+		for path, v := range appendValues {
+			for i, value := range v {
+				intIndex, ok, err := appendIndex(i)
+				if err != nil {
+					return err
+				} else if !ok {
+					break
+				}
+				if elementValues[intIndex] == nil {
+					elementValues[intIndex] = scratchFrom(ctx).dataMap()
+				}
+				elementValues[intIndex][path] = []string{value}
+			}
+		}
+		for path, v := range appendFiles {
+			for i, file := range v {
+				intIndex, ok, err := appendIndex(i)
+				if err != nil {
+					return err
+				} else if !ok {
+					break
+				}
+				if elementFiles[intIndex] == nil {
+					elementFiles[intIndex] = map[string][]*multipart.FileHeader{}
+				}
+				elementFiles[intIndex][path] = []*multipart.FileHeader{file}
 			}
+		}
+	}
+	if truncated {
+		b.warn(fmt.Sprintf("%s array truncated to the maximum allowed size of %d", inputFieldName, maxArraySize))
+	}
+	if maxIndex < 0 {
+		return nil
+	}
 
-			// check if the slice has already been created
-			slice := structValue
-			if slice.Len() == 0 {
-				// create a slice with enough capacity
-				slice = reflect.MakeSlice(structValue.Type(), intIndex+1, intIndex+1)
-			} else if slice.Len() <= intIndex {
-				// create a new slice with enough capacity
-				newSlice := reflect.MakeSlice(structValue.Type(), intIndex+1, intIndex+1)
-				reflect.Copy(newSlice, slice)
-				slice = newSlice
+	// apply the sparse array policy on the indices found
+	if b.SparseArrayPolicy != SparseArrayZeroFill {
+		present := map[int]bool{}
+		for i := range scalars {
+			present[i] = true
+		}
+		for i := range elementValues {
+			present[i] = true
+		}
+		for i := range elementFiles {
+			present[i] = true
+		}
+		if len(present) != maxIndex+1 {
+			if b.SparseArrayPolicy == SparseArrayError {
+				return fmt.Errorf("%s: %w", inputFieldName, ErrSparseArray)
+			}
+			indices := make([]int, 0, len(present))
+			for i := range present {
+				indices = append(indices, i)
 			}
-			if err := setWithProperType(structValue.Type().Elem().Kind(), v[0], slice.Index(intIndex)); err != nil {
-				return err
+			sort.Ints(indices)
+			compactScalars := map[int][]string{}
+			compactValues := map[int]map[string][]string{}
+			compactFiles := map[int]map[string][]*multipart.FileHeader{}
+			for position, i := range indices {
+				if v, ok := scalars[i]; ok {
+					compactScalars[position] = v
+				}
+				if v, ok := elementValues[i]; ok {
+					compactValues[position] = v
+				}
+				if v, ok := elementFiles[i]; ok {
+					compactFiles[position] = v
+				}
 			}
+			scalars, elementValues, elementFiles = compactScalars, compactValues, compactFiles
+			maxIndex = len(indices) - 1
+		}
+	}
+
+	// grow the slice keeping the elements already bound
+	slice := structValue
+	if slice.Len() <= maxIndex {
+		slice = reflect.MakeSlice(structValue.Type(), maxIndex+1, maxIndex+1)
+		reflect.Copy(slice, structValue)
+	}
 
-			structValue.Set(slice)
+	elemKind := structValue.Type().Elem().Kind()
+	for intIndex, v := range scalars {
+		value := b.normalizeValues(field, tag, v[:1])[0]
+		if err := b.setWithProperType(elemKind, value, slice.Index(intIndex)); err != nil {
+			return unsupportedFieldType(err, inputFieldName)
 		}
 	}
+	for intIndex := 0; intIndex <= maxIndex; intIndex++ {
+		if elementValues[intIndex] == nil && elementFiles[intIndex] == nil {
+			continue
+		}
+		elem := slice.Index(intIndex)
+		if elemKind == reflect.Ptr {
+			if elem.IsNil() {
+				elem.Set(reflect.New(elem.Type().Elem()))
+			}
+		} else {
+			elem = elem.Addr()
+		}
+		if err := b.bindData(ctx, elem.Interface(), elementValues[intIndex], tag, elementFiles[intIndex]); err != nil {
+			return err
+		}
+	}
+
+	structValue.Set(slice)
 	return nil
 }
@@ -0,0 +1,51 @@
+package binder
+
+import (
+	"context"
+	"errors"
+	"reflect"
+)
+
+// ConvertOptions describe the values converted by SetValueFromStrings.
+type ConvertOptions struct {
+	Field   reflect.StructField // struct field of the value, for its tag options (converter, layout, trim...), optional
+	Tag     string              // source tag holding the options of the field, i.e. query
+	Key     string              // name of the values, prefixing the errors and passed to BindKeyUnmarshaler
+	Context context.Context     // context passed to BindContextUnmarshaler, context.Background() when nil
+}
+
+// SetValueFromStrings converts the values into the settable value with the same rules the binder applies to
+// param, query, form and header values: the tag options of the field, the named and type converters, enums,
+// the param unmarshalers, sql.Scanner and the builtin kinds, including slices and pointers.
+// Frameworks can use it to bind their own sources consistently.
+func (b *DefaultBinder) SetValueFromStrings(value reflect.Value, values []string, opts ConvertOptions) error {
+	if !value.CanSet() {
+		return errors.New("binder: value is not settable")
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	field := opts.Field
+	if field.Type == nil {
+		field.Type = value.Type()
+	}
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	values = b.normalizeValues(field, opts.Tag, values)
+	if delimiter := b.valuesDelimiter(field, opts.Tag); delimiter != "" {
+		values = splitValues(values, delimiter)
+	}
+	return b.setFieldValues(ctx, value, field, opts.Tag, opts.Key, opts.Key, values)
+}
+
+// SetValueFromStrings converts the values into the settable value with the default binder,
+// see DefaultBinder.SetValueFromStrings.
+func SetValueFromStrings(value reflect.Value, values []string, opts ConvertOptions) error {
+	b, ok := GetBinder().(*DefaultBinder)
+	if !ok {
+		b = NewBinder()
+	}
+	return b.SetValueFromStrings(value, values, opts)
+}
@@ -0,0 +1,70 @@
+package binder
+
+import (
+	"net/netip"
+	"reflect"
+	"slices"
+)
+
+// Clone returns a copy of the binder sharing no mutable state with it: its maps, slices, CSRF source and
+// client IP resolver are copied, and the binder methods found in TypeConverters are bound to the copy. Per-route binders can be derived from a shared one without data races.
+func (b *DefaultBinder) Clone() *DefaultBinder {
+	c := b.clone()
+	if b.CSRF != nil {
+		csrf := *b.CSRF
+		c.CSRF = &csrf
+	}
+	if b.ClientIPResolver != nil {
+		resolver := *b.ClientIPResolver
+		resolver.TrustedProxies = append([]netip.Prefix(nil), b.ClientIPResolver.TrustedProxies...)
+		c.ClientIPResolver = &resolver
+	}
+	return c
+}
+
+// With returns a copy of the binder modified by fn, i.e. `b.With(func(c *binder.DefaultBinder) { c.TrimStrings = true })`.
+func (b *DefaultBinder) With(fn func(c *DefaultBinder)) *DefaultBinder {
+	c := b.Clone()
+	fn(c)
+	return c
+}
+
+// WithMaxBodySize returns a copy of the binder with the given max body size, i.e. for upload routes.
+func (b *DefaultBinder) WithMaxBodySize(size int64) *DefaultBinder {
+	return b.With(func(c *DefaultBinder) { c.MaxBodySize = size })
+}
+
+// WithMaxArraySize returns a copy of the binder with the given max array size.
+func (b *DefaultBinder) WithMaxArraySize(size int) *DefaultBinder {
+	return b.With(func(c *DefaultBinder) { c.MaxArraySize = size })
+}
+
+// WithStrictKeys returns a copy of the binder rejecting, or not, the unknown query and form keys.
+func (b *DefaultBinder) WithStrictKeys(strict bool) *DefaultBinder {
+	return b.With(func(c *DefaultBinder) { c.StrictKeys = strict })
+}
+
+// WithTimeLayout returns a copy of the binder parsing time.Time fields with the given layout.
+func (b *DefaultBinder) WithTimeLayout(layout string) *DefaultBinder {
+	return b.With(func(c *DefaultBinder) { c.TimeLayout = layout })
+}
+
+// WithConverter returns a copy of the binder with the named converter registered.
+func (b *DefaultBinder) WithConverter(name string, fn ConverterFunc) *DefaultBinder {
+	return b.With(func(c *DefaultBinder) { c.RegisterConverter(name, fn) })
+}
+
+// WithTypeConverter returns a copy of the binder with the type converter registered.
+func (b *DefaultBinder) WithTypeConverter(typ reflect.Type, fn ConverterFunc) *DefaultBinder {
+	return b.With(func(c *DefaultBinder) { c.RegisterTypeConverter(typ, fn) })
+}
+
+// WithDeserializer returns a copy of the binder with the body deserializer of the media type registered.
+func (b *DefaultBinder) WithDeserializer(mediaType string, deserializer Deserializer) *DefaultBinder {
+	return b.With(func(c *DefaultBinder) { c.RegisterDeserializer(mediaType, deserializer) })
+}
+
+// WithBindOrder returns a copy of the binder binding the named sources, i.e. `b.WithBindOrder(SourceQuery)`.
+func (b *DefaultBinder) WithBindOrder(sources ...string) *DefaultBinder {
+	return b.With(func(c *DefaultBinder) { c.BindOrder = slices.Clone(sources) })
+}